@@ -33,20 +33,32 @@ type OriginConfig struct {
 }
 
 type OptionalConfig struct {
-	UI          bool   `json:"ui"`
-	Dedup       bool   `json:"dedup"`
-	DirOnly     bool   `json:"dir"`
-	VulnOnly    bool   `json:"vuln"`
-	SaveDev     bool   `json:"dev"`
-	ProgressBar bool   `json:"progress"`
-	TLSVerify   bool   `json:"tls"`
-	Proxy       string `json:"proxy"`
+	UI          bool         `json:"ui"`
+	Dedup       bool         `json:"dedup"`
+	DirOnly     bool         `json:"dir"`
+	VulnOnly    bool         `json:"vuln"`
+	SaveDev     bool         `json:"dev"`
+	ProgressBar bool         `json:"progress"`
+	TLSVerify   bool         `json:"tls"`
+	Proxy       string       `json:"proxy"`
+	JsLib       string       `json:"jslib"`
+	Python      PythonConfig `json:"python"`
+}
+
+type PythonConfig struct {
+	PythonVersion   string   `json:"python_version"`
+	SysPlatform     string   `json:"sys_platform"`
+	PlatformMachine string   `json:"platform_machine"`
+	Extras          []string `json:"extras"`
+	SetupExec       bool     `json:"setup_exec"`
 }
 
 type RepoConfig struct {
 	Maven    []common.RepoConfig `json:"maven"`
 	Npm      []common.RepoConfig `json:"npm"`
 	Composer []common.RepoConfig `json:"composer"`
+	Pypi     []common.RepoConfig `json:"pypi"`
+	Goproxy  []common.RepoConfig `json:"goproxy"`
 }
 
 type SqlOrigin struct {
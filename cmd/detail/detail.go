@@ -17,6 +17,13 @@ type DepDetailGraph struct {
 	Dep
 	ID                      string            `json:"id,omitempty" xml:"id,omitempty"`
 	Develop                 bool              `json:"dev,omitempty" xml:"dev,omitempty"`
+	Scope                   string            `json:"scope,omitempty" xml:"scope,omitempty"`
+	Optional                bool              `json:"optional,omitempty" xml:"optional,omitempty"`
+	Local                   bool              `json:"local,omitempty" xml:"local,omitempty"`
+	Override                string            `json:"override,omitempty" xml:"override,omitempty"`
+	Integrity               string            `json:"integrity,omitempty" xml:"integrity,omitempty"`
+	Source                  string            `json:"source,omitempty" xml:"source,omitempty"`
+	Revision                string            `json:"revision,omitempty" xml:"revision,omitempty"`
 	Direct                  bool              `json:"direct,omitempty" xml:"direct,omitempty"`
 	Paths                   []string          `json:"paths,omitempty" xml:"paths,omitempty"`
 	Licenses                []*License        `json:"licenses,omitempty" xml:"licenses,omitempty"`
@@ -78,6 +85,13 @@ func (d *DepDetailGraph) Update(dep *model.DepGraph) {
 	}
 	d.Direct = dep.Direct
 	d.Develop = dep.Develop
+	d.Scope = string(dep.Scope)
+	d.Optional = dep.Optional
+	d.Local = dep.Local
+	d.Override = dep.Override
+	d.Integrity = dep.Integrity
+	d.Source = dep.Source
+	d.Revision = dep.Revision
 	for _, lic := range dep.Licenses {
 		d.Licenses = append(d.Licenses, &License{ShortName: lic})
 	}
@@ -186,6 +200,20 @@ func vulnLanguageKey(language model.Language) []string {
 		return []string{"ruby"}
 	case model.Lan_Rust:
 		return []string{"rust"}
+	case model.Lan_Erlang:
+		return []string{"erlang"}
+	case model.Lan_Elixir:
+		return []string{"elixir"}
+	case model.Lan_DotNet:
+		return []string{"dotnet"}
+	case model.Lan_Swift:
+		return []string{"swift"}
+	case model.Lan_CocoaPods:
+		return []string{"cocoapods"}
+	case model.Lan_Dart:
+		return []string{"dart"}
+	case model.Lan_Runtime:
+		return []string{"runtime"}
 	default:
 		return []string{}
 	}
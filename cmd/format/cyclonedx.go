@@ -28,14 +28,23 @@ func cyclonedxbom(dep *detail.DepDetailGraph) *cyclonedx.BOM {
 		}
 
 		if n.Name != "" {
-			components = append(components, cyclonedx.Component{
+			component := cyclonedx.Component{
 				BOMRef:     "ref-" + n.ID,
 				Type:       cyclonedx.ComponentTypeLibrary,
 				Author:     n.Vendor,
 				Name:       n.Name[strings.LastIndex(n.Name, "/")+1:],
 				Version:    n.Version,
 				PackageURL: n.Purl(),
-			})
+			}
+			// 组件来源仓库
+			if n.Source != "" {
+				component.ExternalReferences = &[]cyclonedx.ExternalReference{{
+					URL:     n.Source,
+					Type:    cyclonedx.ERTypeVCS,
+					Comment: n.Revision,
+				}}
+			}
+			components = append(components, component)
 			var deps []string
 			for _, child := range n.Children {
 				deps = append(deps, child.Purl())
@@ -21,9 +21,12 @@ import (
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/common"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/golang"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/java"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/javascript"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/jslib"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/php"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/python"
 )
 
 var version string
@@ -152,6 +155,23 @@ func args() {
 	java.RegisterMavenRepo(config.Conf().Repo.Maven...)
 	javascript.RegisterNpmRepo(config.Conf().Repo.Npm...)
 	php.RegisterComposerRepo(config.Conf().Repo.Composer...)
+	python.RegisterPypiRepo(config.Conf().Repo.Pypi...)
+	golang.RegisterGoproxyRepo(config.Conf().Repo.Goproxy...)
+	python.RegisterPyEnv(python.PyEnv{
+		PythonVersion:   config.Conf().Optional.Python.PythonVersion,
+		SysPlatform:     config.Conf().Optional.Python.SysPlatform,
+		PlatformMachine: config.Conf().Optional.Python.PlatformMachine,
+		Extras:          config.Conf().Optional.Python.Extras,
+	})
+	python.RegisterSetupExec(config.Conf().Optional.Python.SetupExec)
+
+	if sigs := config.Conf().Optional.JsLib; sigs != "" {
+		if data, err := os.ReadFile(sigs); err != nil {
+			logs.Warnf("read js signatures %s error: %v", sigs, err)
+		} else if err := jslib.RegisterSignatures(data); err != nil {
+			logs.Warnf("load js signatures %s error: %v", sigs, err)
+		}
+	}
 }
 
 func initHttpClient() {
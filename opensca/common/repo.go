@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
@@ -40,6 +42,15 @@ func DownloadUrlFromRepos(route string, do func(repo RepoConfig, r io.Reader), r
 		repoSet[repo.Url] = true
 
 		url := fmt.Sprintf("%s/%s", strings.TrimRight(repo.Url, "/"), strings.TrimLeft(route, "/"))
+
+		// 本地仓库
+		if strings.HasPrefix(url, "file://") {
+			if downloadFile(url, func(r io.Reader) { do(repo, r) }) {
+				return true
+			}
+			continue
+		}
+
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
 			logs.Warn(err)
@@ -70,3 +81,21 @@ func DownloadUrlFromRepos(route string, do func(repo RepoConfig, r io.Reader), r
 	}
 	return false
 }
+
+// downloadFile 读取file://格式的本地文件
+func downloadFile(url string, do func(r io.Reader)) bool {
+	path := strings.TrimPrefix(url, "file://")
+	// file:///C:/dir
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	f, err := os.Open(filepath.FromSlash(path))
+	if err != nil {
+		logs.Debugf("%s %s", err, url)
+		return false
+	}
+	defer f.Close()
+	logs.Debugf("open %s", url)
+	do(f)
+	return true
+}
@@ -24,8 +24,25 @@ type DepGraph struct {
 	licenseMap map[string]bool
 	// 仅用于开发环境
 	Develop bool
+	// 作用域
+	Scope Scope
+	// 可选依赖
+	Optional bool
+	// 项目内的本地组件 如workspace中的其他项目
+	Local bool
+	// 生效的版本覆盖规则 如npm overrides/yarn resolutions中指定的版本
+	Override string
+	// 完整性校验值 如go.sum中的h1哈希
+	Integrity string
+	// 组件来源 如git仓库地址
+	Source string
+	// 来源中锁定的修订版本 如git提交
+	Revision string
 	// 直接依赖
 	Direct bool
+	// 间接依赖 用于依赖文件中只记录了组件、没有记录依赖关系的间接依赖
+	// 这类组件挂载在根节点下 但不应视为直接依赖
+	Transitive bool
 	// 父节点
 	Parents []*DepGraph
 	pset    map[*DepGraph]bool
@@ -57,6 +74,14 @@ func (dep *DepGraph) AppendChild(child *DepGraph) {
 	}
 }
 
+// AppendRuntime 添加项目使用的运行时组件 version为空时忽略
+func (dep *DepGraph) AppendRuntime(name, version string) {
+	if dep == nil || version == "" {
+		return
+	}
+	dep.AppendChild(&DepGraph{Name: name, Version: version, Language: Lan_Runtime})
+}
+
 // RemoveChild 移除子依赖
 func (dep *DepGraph) RemoveChild(child *DepGraph) {
 	for i, c := range dep.Children {
@@ -189,7 +214,7 @@ func (dep *DepGraph) Build(deep bool, lan Language) {
 			n.Language = lan
 		}
 		// 直接依赖
-		if len(n.Parents) == 0 || len(p.Parents) == 0 {
+		if (len(n.Parents) == 0 || len(p.Parents) == 0) && !n.Transitive {
 			n.Direct = true
 		}
 		return true
@@ -16,17 +16,76 @@ const (
 	Lan_Golang     Language = "Golang"
 	Lan_Rust       Language = "Rust"
 	Lan_Erlang     Language = "Erlang"
+	Lan_Elixir     Language = "Elixir"
 	Lan_Python     Language = "Python"
+	Lan_DotNet     Language = "DotNet"
+	Lan_Swift      Language = "Swift"
+	Lan_CocoaPods  Language = "CocoaPods" // CocoaPods组件可能为Objective-C或Swift实现
+	Lan_Dart       Language = "Dart"
+	Lan_Runtime    Language = "Runtime" // 运行时组件 如go标准库、jdk、.NET运行时、dart/flutter sdk、node、python及php解释器
 )
 
+// 运行时组件名称
+const (
+	Runtime_Go     = "go"
+	Runtime_Jdk    = "jdk"
+	Runtime_Node   = "node"
+	Runtime_Python = "python"
+	Runtime_Php    = "php"
+	// .NET (Core) 及 .NET Framework
+	Runtime_DotNet          = "dotnet"
+	Runtime_DotNetFramework = "dotnet-framework"
+	// Dart及Flutter sdk
+	Runtime_Dart    = "dart"
+	Runtime_Flutter = "flutter"
+)
+
+// runtimePurl 运行时组件在所属生态中的purl类型及组件名
+type runtimePurl struct {
+	Type string
+	Name string
+}
+
+// runtimePurls 有生态内标识的运行时组件
+// go标准库的漏洞以golang/stdlib发布 .NET运行时以Microsoft.NETCore.App发布到nuget
+var runtimePurls = map[string]runtimePurl{
+	Runtime_Go:     {"golang", "stdlib"},
+	Runtime_DotNet: {"nuget", "Microsoft.NETCore.App"},
+}
+
+// runtimeEcosystems 其余运行时组件使用generic类型 并以ecosystem限定来源生态
+var runtimeEcosystems = map[string]string{
+	Runtime_Jdk:             "maven",
+	Runtime_Node:            "npm",
+	Runtime_Python:          "pypi",
+	Runtime_Php:             "composer",
+	Runtime_DotNetFramework: "nuget",
+	Runtime_Dart:            "pub",
+	Runtime_Flutter:         "pub",
+}
+
+// runtimeEcosystem 运行时组件所属生态 php扩展(ext-*)属于composer
+func runtimeEcosystem(name string) string {
+	if strings.HasPrefix(name, "ext-") {
+		return "composer"
+	}
+	return runtimeEcosystems[name]
+}
+
 var purlRmap = map[string]Language{
-	"cargo":    Lan_Rust,
-	"composer": Lan_Php,
-	"gem":      Lan_Ruby,
-	"golang":   Lan_Golang,
-	"maven":    Lan_Java,
-	"npm":      Lan_JavaScript,
-	"pypi":     Lan_Python,
+	"cargo":     Lan_Rust,
+	"cocoapods": Lan_CocoaPods,
+	"composer":  Lan_Php,
+	"gem":       Lan_Ruby,
+	"generic":   Lan_Runtime,
+	"golang":    Lan_Golang,
+	"hex":       Lan_Elixir,
+	"maven":     Lan_Java,
+	"npm":       Lan_JavaScript,
+	"nuget":     Lan_DotNet,
+	"pub":       Lan_Dart,
+	"pypi":      Lan_Python,
+	"swift":     Lan_Swift,
 }
 
 var purlMap = map[Language]string{}
@@ -35,9 +94,19 @@ func init() {
 	for k, v := range purlRmap {
 		purlMap[v] = k
 	}
+	// rebar使用的组件同样来自hex仓库
+	purlMap[Lan_Erlang] = "hex"
 }
 
 func Purl(vendor, name, version string, language Language) string {
+	if language == Lan_Runtime {
+		if p, ok := runtimePurls[name]; ok {
+			return fmt.Sprintf("pkg:%s/%s@%s", p.Type, p.Name, version)
+		}
+		if eco := runtimeEcosystem(name); eco != "" {
+			return fmt.Sprintf("pkg:generic/%s@%s?ecosystem=%s", name, version, eco)
+		}
+	}
 	pkg := ""
 	if g, ok := purlMap[language]; ok {
 		pkg = g
@@ -56,13 +125,15 @@ func ParsePurl(purl string) (vendor, name, version string, language Language) {
 		purl = purl[:i]
 	}
 
+	typ := ""
 	if i := strings.Index(purl, "/"); i == -1 {
 		return
 	} else {
 		if pkg := strings.Split(purl[:i], `:`); len(pkg) != 2 {
 			return
 		} else {
-			if l, ok := purlRmap[pkg[1]]; ok {
+			typ = pkg[1]
+			if l, ok := purlRmap[typ]; ok {
 				language = l
 			}
 		}
@@ -76,7 +147,15 @@ func ParsePurl(purl string) (vendor, name, version string, language Language) {
 		name = purl[:i]
 	}
 
-	if language == Lan_Java {
+	// 生态内标识的运行时组件
+	for runtime, p := range runtimePurls {
+		if p.Type == typ && p.Name == name {
+			return "", runtime, version, Lan_Runtime
+		}
+	}
+
+	// swift组件的namespace为仓库地址 如github.com/apple
+	if language == Lan_Java || language == Lan_Swift {
 		if i := strings.LastIndex(name, "/"); i != -1 {
 			vendor = name[:i]
 			name = name[i+1:]
@@ -0,0 +1,41 @@
+package model
+
+// Scope 组件作用域
+type Scope string
+
+const (
+	Scope_None Scope = ""
+	// 编译及运行时使用
+	Scope_Compile Scope = "compile"
+	// 仅运行时使用
+	Scope_Runtime Scope = "runtime"
+	// 仅测试使用
+	Scope_Test Scope = "test"
+	// 仅构建过程使用(注解处理器等)
+	Scope_Build Scope = "build"
+	// 构建工具插件及扩展 构建时以完整权限运行 不视为开发组件
+	Scope_Plugin Scope = "plugin"
+)
+
+// Develop 该作用域的组件是否仅用于开发环境
+func (s Scope) Develop() bool {
+	return s == Scope_Test || s == Scope_Build
+}
+
+// scopeLevel 作用域优先级 同一组件存在多个作用域时保留优先级高的
+var scopeLevel = map[Scope]int{
+	Scope_None:    0,
+	Scope_Build:   1,
+	Scope_Plugin:  2,
+	Scope_Test:    3,
+	Scope_Runtime: 4,
+	Scope_Compile: 5,
+}
+
+// Merge 合并作用域 返回优先级更高的作用域
+func (s Scope) Merge(other Scope) Scope {
+	if scopeLevel[other] > scopeLevel[s] {
+		return other
+	}
+	return s
+}
@@ -53,6 +53,14 @@ func Path(vendor, name, version string, language model.Language) string {
 		path = filepath.Join(cacheDir, "npm", fmt.Sprintf("%s.json", name))
 	case model.Lan_Php:
 		path = filepath.Join(cacheDir, "composer", fmt.Sprintf("%s.json", name))
+	case model.Lan_Python:
+		if version == "" {
+			path = filepath.Join(cacheDir, "pypi", fmt.Sprintf("%s.json", name))
+		} else {
+			path = filepath.Join(cacheDir, "pypi", name, fmt.Sprintf("%s.json", version))
+		}
+	case model.Lan_Golang:
+		path = filepath.Join(cacheDir, "goproxy", name, "@v", fmt.Sprintf("%s.mod", version))
 	default:
 		path = filepath.Join(cacheDir, "none", fmt.Sprintf("%s-%s-%s", vendor, name, version))
 	}
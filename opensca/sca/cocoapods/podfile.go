@@ -0,0 +1,120 @@
+package cocoapods
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"gopkg.in/yaml.v3"
+)
+
+// podfileLock Podfile.lock
+// https://guides.cocoapods.org/using/using-cocoapods.html#what-is-podfilelock
+type podfileLock struct {
+	// - Alamofire (5.8.1)
+	// - FirebaseCore (10.18.0):
+	//   - FirebaseCoreInternal (~> 10.0)
+	Pods []any `yaml:"PODS"`
+	// - Alamofire (~> 5.8)
+	Dependencies []string `yaml:"DEPENDENCIES"`
+	// key:组件名 value: :path/:git/:podspec
+	ExternalSources map[string]map[string]string `yaml:"EXTERNAL SOURCES"`
+	// key:组件名 value: :git/:commit/:tag
+	CheckoutOptions map[string]map[string]string `yaml:"CHECKOUT OPTIONS"`
+	// key:组件名 value:podspec的sha1
+	SpecChecksums map[string]string `yaml:"SPEC CHECKSUMS"`
+}
+
+// parsePod 解析组件描述
+// Firebase/Analytics (10.18.0) => Firebase 10.18.0
+// MyLib (from `../MyLib`) => MyLib from `../MyLib`
+// 子模块(subspec)归属于所在的组件
+func parsePod(s string) (name, version string) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, " ("); i != -1 {
+		name, version = s[:i], strings.TrimSuffix(s[i+2:], ")")
+	} else {
+		name = s
+	}
+	if i := strings.Index(name, "/"); i != -1 {
+		name = name[:i]
+	}
+	return strings.Trim(name, `"`), version
+}
+
+// ParsePodfileLock 解析Podfile.lock
+func ParsePodfileLock(file *model.File) *model.DepGraph {
+
+	lock := &podfileLock{}
+	file.OpenReader(func(reader io.Reader) {
+		if err := yaml.NewDecoder(reader).Decode(lock); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph {
+		return &model.DepGraph{Name: s[0]}
+	}).LoadOrStore
+
+	// 锁定的组件及其依赖 key:组件名
+	pods := map[string]*model.DepGraph{}
+	requires := map[string][]string{}
+	for _, item := range lock.Pods {
+		switch pod := item.(type) {
+		case string:
+			name, version := parsePod(pod)
+			pods[name] = _dep(name)
+			pods[name].Version = version
+		case map[string]any:
+			for spec, subs := range pod {
+				name, version := parsePod(spec)
+				pods[name] = _dep(name)
+				pods[name].Version = version
+				list, _ := subs.([]any)
+				for _, sub := range list {
+					requires[name] = append(requires[name], fmt.Sprint(sub))
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(pods))
+	for name := range pods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		dep := pods[name]
+		dep.Integrity = lock.SpecChecksums[name]
+		if _, ok := lock.ExternalSources[name][":path"]; ok {
+			dep.Local = true
+		}
+		for _, req := range requires[name] {
+			sub, _ := parsePod(req)
+			// 依赖同一组件的其他子模块
+			if sub == name {
+				continue
+			}
+			dep.AppendChild(pods[sub])
+		}
+	}
+
+	root := &model.DepGraph{Path: file.Relpath()}
+	for _, req := range lock.Dependencies {
+		name, _ := parsePod(req)
+		root.AppendChild(pods[name])
+	}
+
+	// 没有被引用的组件
+	for _, name := range names {
+		if dep := pods[name]; len(dep.Parents) == 0 {
+			root.AppendChild(dep)
+		}
+	}
+
+	return root
+}
@@ -0,0 +1,26 @@
+package cocoapods
+
+import (
+	"context"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+type Sca struct{}
+
+func (sca Sca) Language() model.Language {
+	return model.Lan_CocoaPods
+}
+
+func (sca Sca) Filter(relpath string) bool {
+	return filter.CocoaPodsPodfileLock(relpath)
+}
+
+func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
+	for _, f := range files {
+		if sca.Filter(f.Relpath()) {
+			call(f, ParsePodfileLock(f))
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package dart
+
+import (
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"gopkg.in/yaml.v3"
+)
+
+// pubRequire pubspec.yaml中声明的依赖
+// http: ^1.1.0
+// local_pkg: {path: ../local_pkg}
+// flutter: {sdk: flutter}
+type pubRequire struct {
+	Version string
+	// path引用的本地组件
+	Local bool
+	// sdk提供的组件 如flutter、flutter_test
+	Sdk string
+}
+
+func (r *pubRequire) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		r.Version = value.Value
+		return nil
+	}
+	v := struct {
+		Version string `yaml:"version"`
+		Path    string `yaml:"path"`
+		Sdk     string `yaml:"sdk"`
+	}{}
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	r.Version = v.Version
+	r.Local = v.Path != ""
+	r.Sdk = v.Sdk
+	return nil
+}
+
+// Pubspec pubspec.yaml
+// https://dart.dev/tools/pub/pubspec
+type Pubspec struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	// sdk: ">=3.0.0 <4.0.0" flutter: ">=3.10.0"
+	Environment         map[string]string      `yaml:"environment"`
+	Dependencies        map[string]*pubRequire `yaml:"dependencies"`
+	DevDependencies     map[string]*pubRequire `yaml:"dev_dependencies"`
+	DependencyOverrides map[string]*pubRequire `yaml:"dependency_overrides"`
+	File                *model.File            `yaml:"-"`
+}
+
+// ReadPubspec 读取pubspec.yaml
+func ReadPubspec(file *model.File) *Pubspec {
+	spec := &Pubspec{File: file}
+	file.OpenReader(func(reader io.Reader) {
+		if err := yaml.NewDecoder(reader).Decode(spec); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+	return spec
+}
+
+// dir pubspec.yaml所在目录
+func (spec *Pubspec) dir() string {
+	return path.Dir(strings.ReplaceAll(spec.File.Relpath(), `\`, `/`))
+}
+
+// develop 是否为仅用于开发的直接依赖
+func (spec *Pubspec) develop(name string) bool {
+	if spec == nil {
+		return false
+	}
+	_, dev := spec.DevDependencies[name]
+	_, prod := spec.Dependencies[name]
+	return dev && !prod
+}
+
+// sortedKeys 按字典序排列的key
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendSdks 添加sdk版本约束对应的运行时组件
+func appendSdks(root *model.DepGraph, sdks map[string]string) {
+	for _, name := range sortedKeys(sdks) {
+		switch name {
+		case "dart", "sdk":
+			root.AppendRuntime(model.Runtime_Dart, sdks[name])
+		case "flutter":
+			root.AppendRuntime(model.Runtime_Flutter, sdks[name])
+		}
+	}
+}
+
+// ParsePubspec 解析没有pubspec.lock的pubspec.yaml
+func ParsePubspec(spec *Pubspec) *model.DepGraph {
+
+	root := &model.DepGraph{Name: spec.Name, Version: spec.Version, Path: spec.File.Relpath()}
+	appendSdks(root, spec.Environment)
+
+	added := map[string]bool{}
+	for _, reqs := range []map[string]*pubRequire{spec.Dependencies, spec.DevDependencies} {
+		for _, name := range sortedKeys(reqs) {
+			req := reqs[name]
+			if req == nil {
+				req = &pubRequire{}
+			}
+			// sdk中的组件随运行时提供
+			if req.Sdk != "" || added[name] {
+				continue
+			}
+			added[name] = true
+			dep := &model.DepGraph{
+				Name:    name,
+				Version: req.Version,
+				Develop: spec.develop(name),
+				Local:   req.Local,
+			}
+			if o, ok := spec.DependencyOverrides[name]; ok && o != nil {
+				dep.Version = o.Version
+				dep.Override = o.Version
+				dep.Local = o.Local
+			}
+			root.AppendChild(dep)
+		}
+	}
+
+	return root
+}
+
+// pubspecLock pubspec.lock
+type pubspecLock struct {
+	// key:组件名
+	Packages map[string]struct {
+		// direct main|direct dev|direct overridden|transitive
+		Dependency string `yaml:"dependency"`
+		// hosted: {name, sha256, url} git: {url, ref, resolved-ref, path} path: {path, relative} sdk: flutter
+		Description any `yaml:"description"`
+		// hosted|git|path|sdk
+		Source  string `yaml:"source"`
+		Version string `yaml:"version"`
+	} `yaml:"packages"`
+	// dart: ">=3.0.0 <4.0.0" flutter: ">=3.10.0"
+	Sdks map[string]string `yaml:"sdks"`
+}
+
+// ParsePubspecLock 解析pubspec.lock
+// pubspec.lock中不包含组件间的依赖关系 间接依赖挂载在根节点下并标记为间接依赖
+// spec: 同目录下的pubspec.yaml 可以为nil
+func ParsePubspecLock(file *model.File, spec *Pubspec) *model.DepGraph {
+
+	lock := &pubspecLock{}
+	file.OpenReader(func(reader io.Reader) {
+		if err := yaml.NewDecoder(reader).Decode(lock); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	root := &model.DepGraph{Path: file.Relpath()}
+	if spec != nil {
+		root.Name = spec.Name
+		root.Version = spec.Version
+	}
+	appendSdks(root, lock.Sdks)
+
+	for _, name := range sortedKeys(lock.Packages) {
+
+		pkg := lock.Packages[name]
+		// sdk中的组件随运行时提供
+		if pkg.Source == "sdk" {
+			continue
+		}
+
+		dep := &model.DepGraph{Name: name, Version: pkg.Version}
+		desc, _ := pkg.Description.(map[string]any)
+		switch pkg.Source {
+		case "hosted":
+			dep.Integrity, _ = desc["sha256"].(string)
+		case "git":
+			// 仓库地址及锁定的提交
+			dep.Source, _ = desc["url"].(string)
+			dep.Revision, _ = desc["resolved-ref"].(string)
+		case "path":
+			dep.Local = true
+		}
+
+		switch pkg.Dependency {
+		case "transitive":
+			dep.Transitive = true
+		case "direct dev":
+			dep.Develop = true
+		case "direct overridden":
+			dep.Override = pkg.Version
+			dep.Develop = spec.develop(name)
+		}
+
+		root.AppendChild(dep)
+	}
+
+	return root
+}
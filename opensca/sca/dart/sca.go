@@ -0,0 +1,49 @@
+package dart
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+type Sca struct{}
+
+func (sca Sca) Language() model.Language {
+	return model.Lan_Dart
+}
+
+func (sca Sca) Filter(relpath string) bool {
+	return filter.DartPubspecLock(relpath) || filter.DartPubspecYaml(relpath)
+}
+
+func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
+
+	// map[dir]
+	locks := map[string]*model.File{}
+	var specs []*Pubspec
+	for _, f := range files {
+		if filter.DartPubspecLock(f.Relpath()) {
+			locks[path.Dir(strings.ReplaceAll(f.Relpath(), `\`, `/`))] = f
+		}
+		if filter.DartPubspecYaml(f.Relpath()) {
+			specs = append(specs, ReadPubspec(f))
+		}
+	}
+
+	for _, spec := range specs {
+		if lock, ok := locks[spec.dir()]; ok {
+			call(lock, ParsePubspecLock(lock, spec))
+			delete(locks, spec.dir())
+		} else {
+			call(spec.File, ParsePubspec(spec))
+		}
+	}
+
+	// 没有pubspec.yaml的pubspec.lock
+	for _, f := range locks {
+		call(f, ParsePubspecLock(f, nil))
+	}
+}
@@ -0,0 +1,136 @@
+package dotnet
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// projectAssets obj/project.assets.json restore生成的完整依赖图
+type projectAssets struct {
+	Version int `json:"version"`
+	// key:目标框架 value: key:组件名/版本号
+	Targets map[string]map[string]struct {
+		// package|project
+		Type         string            `json:"type"`
+		Dependencies map[string]string `json:"dependencies"`
+	} `json:"targets"`
+	// key:组件名/版本号
+	Libraries map[string]struct {
+		Sha512 string `json:"sha512"`
+		Type   string `json:"type"`
+	} `json:"libraries"`
+	// key:目标框架 value:["Newtonsoft.Json >= 13.0.3"]
+	ProjectFileDependencyGroups map[string][]string `json:"projectFileDependencyGroups"`
+	Project                     struct {
+		Version string `json:"version"`
+		Restore struct {
+			ProjectName string `json:"projectName"`
+		} `json:"restore"`
+		// key:目标框架
+		Frameworks map[string]struct {
+			Dependencies map[string]struct {
+				Version string `json:"version"`
+				// All表示依赖不会传递给引用方 如分析器等开发工具
+				SuppressParent string `json:"suppressParent"`
+			} `json:"dependencies"`
+		} `json:"frameworks"`
+	} `json:"project"`
+}
+
+// ParseProjectAssets 解析project.assets.json
+func ParseProjectAssets(file *model.File) *model.DepGraph {
+
+	assets := &projectAssets{}
+	file.OpenReader(func(reader io.Reader) {
+		if err := json.NewDecoder(reader).Decode(assets); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	root := &model.DepGraph{
+		Name:    assets.Project.Restore.ProjectName,
+		Version: assets.Project.Version,
+		Path:    file.Relpath(),
+	}
+
+	_dep := model.NewDepGraphMap(func(s ...string) string {
+		return strings.ToLower(strings.Join(s, ":"))
+	}, func(s ...string) *model.DepGraph {
+		return &model.DepGraph{Name: s[0], Version: s[1]}
+	}).LoadOrStore
+
+	// 目标框架中的组件 key:目标框架 value: key:小写组件名
+	targets := map[string]map[string]*model.DepGraph{}
+	var tfms []string
+	for _, tfm := range sortedKeys(assets.Targets) {
+		// 指定运行时标识的依赖与目标框架相同
+		if strings.Contains(tfm, "/") {
+			continue
+		}
+		tfms = append(tfms, tfm)
+		pkgs := assets.Targets[tfm]
+		deps := map[string]*model.DepGraph{}
+		for _, key := range sortedKeys(pkgs) {
+			name, version, _ := strings.Cut(key, "/")
+			dep := _dep(name, version)
+			lib := assets.Libraries[key]
+			dep.Integrity = lib.Sha512
+			dep.Local = pkgs[key].Type == "project"
+			deps[strings.ToLower(name)] = dep
+		}
+		for _, key := range sortedKeys(pkgs) {
+			name, _, _ := strings.Cut(key, "/")
+			for sub := range pkgs[key].Dependencies {
+				deps[strings.ToLower(name)].AppendChild(deps[strings.ToLower(sub)])
+			}
+		}
+		targets[tfm] = deps
+	}
+
+	// 直接依赖 key:小写组件名 value:是否仅用于开发
+	direct := map[string]bool{}
+	for _, fw := range assets.Project.Frameworks {
+		for name, d := range fw.Dependencies {
+			direct[strings.ToLower(name)] = strings.EqualFold(d.SuppressParent, "All")
+		}
+	}
+	if len(direct) == 0 {
+		for _, group := range assets.ProjectFileDependencyGroups {
+			for _, req := range group {
+				if fields := strings.Fields(req); len(fields) > 0 {
+					direct[strings.ToLower(fields[0])] = false
+				}
+			}
+		}
+	}
+
+	dev := map[*model.DepGraph]bool{}
+	for _, tfm := range tfms {
+		deps := targets[tfm]
+		for _, name := range sortedKeys(deps) {
+			dep := deps[name]
+			d, ok := direct[name]
+			// 引用的项目不在project.frameworks中
+			if ok || dep.Local && len(dep.Parents) == 0 {
+				root.AppendChild(dep)
+				if d {
+					dev[dep] = true
+				}
+			}
+		}
+		for _, name := range sortedKeys(deps) {
+			if dep := deps[name]; len(dep.Parents) == 0 {
+				root.AppendChild(dep)
+			}
+		}
+	}
+
+	appendFrameworks(root, tfms)
+	markDevelop(root, dev)
+
+	return root
+}
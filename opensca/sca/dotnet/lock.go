@@ -0,0 +1,138 @@
+package dotnet
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// packagesLock packages.lock.json
+// https://learn.microsoft.com/nuget/consume-packages/package-references-in-project-files#locking-dependencies
+type packagesLock struct {
+	Version int `json:"version"`
+	// key:目标框架 value: key:组件名
+	Dependencies map[string]map[string]struct {
+		// Direct|Transitive|Project|CentralTransitive
+		Type         string            `json:"type"`
+		Requested    string            `json:"requested"`
+		Resolved     string            `json:"resolved"`
+		ContentHash  string            `json:"contentHash"`
+		Dependencies map[string]string `json:"dependencies"`
+	} `json:"dependencies"`
+}
+
+// sortedKeys 按字典序排列的key
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// markDevelop 仅被开发依赖引用的组件标记为开发组件
+// dev: 仅用于开发的直接依赖
+func markDevelop(root *model.DepGraph, dev map[*model.DepGraph]bool) {
+	prod := map[*model.DepGraph]bool{}
+	root.ForEachNode(func(p, n *model.DepGraph) bool {
+		if dev[n] {
+			return false
+		}
+		prod[n] = true
+		return true
+	})
+	root.ForEachNode(func(p, n *model.DepGraph) bool {
+		if n.Language != model.Lan_Runtime {
+			n.Develop = !prod[n]
+		}
+		return true
+	})
+}
+
+// ParsePackagesLock 解析packages.lock.json
+// proj: 同目录下的项目文件 用于确定项目名称及开发依赖 可以为nil
+func ParsePackagesLock(file *model.File, proj *Project) *model.DepGraph {
+
+	lock := &packagesLock{}
+	file.OpenReader(func(reader io.Reader) {
+		if err := json.NewDecoder(reader).Decode(lock); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	root := &model.DepGraph{Path: file.Relpath()}
+	if proj != nil {
+		root.Name = proj.Name
+		root.Version = proj.Version
+	}
+
+	_dep := model.NewDepGraphMap(func(s ...string) string {
+		return strings.ToLower(strings.Join(s, ":"))
+	}, func(s ...string) *model.DepGraph {
+		return &model.DepGraph{Name: s[0], Version: s[1]}
+	}).LoadOrStore
+
+	devReq := proj.develop()
+	dev := map[*model.DepGraph]bool{}
+	var frameworks, direct []*model.DepGraph
+	var tfms []string
+
+	for _, tfm := range sortedKeys(lock.Dependencies) {
+		// 指定运行时标识的依赖与目标框架相同
+		if strings.Contains(tfm, "/") {
+			continue
+		}
+		tfms = append(tfms, tfm)
+		pkgs := lock.Dependencies[tfm]
+
+		// 当前框架下的组件 key:小写组件名
+		deps := map[string]*model.DepGraph{}
+		for _, name := range sortedKeys(pkgs) {
+			pkg := pkgs[name]
+			dep := _dep(name, pkg.Resolved)
+			dep.Integrity = pkg.ContentHash
+			dep.Local = pkg.Type == "Project"
+			deps[strings.ToLower(name)] = dep
+			if pkg.Type == "Direct" || pkg.Type == "Project" {
+				direct = append(direct, dep)
+				if d, ok := devReq[strings.ToLower(name)]; ok && d {
+					dev[dep] = true
+				}
+			}
+		}
+		for _, name := range sortedKeys(pkgs) {
+			for sub := range pkgs[name].Dependencies {
+				deps[strings.ToLower(name)].AppendChild(deps[strings.ToLower(sub)])
+			}
+		}
+		for _, dep := range deps {
+			frameworks = append(frameworks, dep)
+		}
+	}
+
+	for _, dep := range direct {
+		// 被其他项目引用的项目不是直接依赖
+		if dep.Local && len(dep.Parents) > 0 {
+			continue
+		}
+		root.AppendChild(dep)
+	}
+	for _, dep := range frameworks {
+		if len(dep.Parents) == 0 {
+			root.AppendChild(dep)
+		}
+	}
+
+	if proj != nil {
+		tfms = proj.Frameworks
+	}
+	appendFrameworks(root, tfms)
+	markDevelop(root, dev)
+
+	return root
+}
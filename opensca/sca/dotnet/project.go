@@ -0,0 +1,340 @@
+package dotnet
+
+import (
+	"encoding/xml"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// packageReference 组件引用
+// <PackageReference Include="Newtonsoft.Json" Version="13.0.3" PrivateAssets="all" />
+type packageReference struct {
+	Include         string `xml:"Include,attr"`
+	Update          string `xml:"Update,attr"`
+	Version         string `xml:"Version,attr"`
+	VersionOverride string `xml:"VersionOverride,attr"`
+	PrivateAssets   string `xml:"PrivateAssets,attr"`
+	// 子元素形式 <Version>13.0.3</Version>
+	VersionElem       string `xml:"Version"`
+	PrivateAssetsElem string `xml:"PrivateAssets"`
+}
+
+// version 声明的版本
+func (r packageReference) version() string {
+	for _, v := range []string{r.VersionOverride, r.Version, r.VersionElem} {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// private 是否为仅用于开发的组件 如分析器、SourceLink等构建工具
+func (r packageReference) private() bool {
+	return strings.EqualFold(strings.TrimSpace(r.PrivateAssets+r.PrivateAssetsElem), "all")
+}
+
+// xmlProperty PropertyGroup中的属性
+type xmlProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// msbuildProject *.csproj/*.fsproj/*.vbproj 及 Directory.Packages.props
+// https://learn.microsoft.com/nuget/consume-packages/package-references-in-project-files
+// https://learn.microsoft.com/nuget/consume-packages/central-package-management
+type msbuildProject struct {
+	PropertyGroups []struct {
+		Properties []xmlProperty `xml:",any"`
+	} `xml:"PropertyGroup"`
+	ItemGroups []struct {
+		PackageReferences       []packageReference `xml:"PackageReference"`
+		PackageVersions         []packageReference `xml:"PackageVersion"`
+		GlobalPackageReferences []packageReference `xml:"GlobalPackageReference"`
+		ProjectReferences       []struct {
+			Include string `xml:"Include,attr"`
+		} `xml:"ProjectReference"`
+	} `xml:"ItemGroup"`
+}
+
+// dotnetRequire 项目声明的依赖
+type dotnetRequire struct {
+	Name    string
+	Version string
+	Develop bool
+	// 引用的其他项目
+	Local bool
+}
+
+// Project 项目文件
+type Project struct {
+	Name    string
+	Version string
+	// 目标框架 net6.0;net48
+	Frameworks []string
+	Requires   []dotnetRequire
+	File       *model.File
+}
+
+// PackagesProps Directory.Packages.props 集中管理的组件版本
+type PackagesProps struct {
+	// key:小写组件名
+	Versions map[string]string
+	// 所有项目都引用的组件
+	Globals []dotnetRequire
+	File    *model.File
+}
+
+// msbuildPropertyReg $(Name)
+var msbuildPropertyReg = regexp.MustCompile(`\$\((\w+)\)`)
+
+// readMsbuild 读取msbuild文件及其中定义的属性
+func readMsbuild(file *model.File) (*msbuildProject, map[string]string) {
+	proj := &msbuildProject{}
+	file.OpenReader(func(reader io.Reader) {
+		if err := xml.NewDecoder(reader).Decode(proj); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+	props := map[string]string{}
+	for _, group := range proj.PropertyGroups {
+		for _, p := range group.Properties {
+			props[p.XMLName.Local] = strings.TrimSpace(p.Value)
+		}
+	}
+	return proj, props
+}
+
+// expand 替换值中引用的属性
+func expand(s string, props ...map[string]string) string {
+	return msbuildPropertyReg.ReplaceAllStringFunc(s, func(m string) string {
+		name := m[2 : len(m)-1]
+		for _, p := range props {
+			if v, ok := p[name]; ok {
+				return v
+			}
+		}
+		return m
+	})
+}
+
+// ReadPackagesProps 读取Directory.Packages.props
+func ReadPackagesProps(file *model.File) *PackagesProps {
+	proj, props := readMsbuild(file)
+	central := &PackagesProps{Versions: map[string]string{}, File: file}
+	for _, group := range proj.ItemGroups {
+		for _, pv := range group.PackageVersions {
+			name := pv.Include
+			if name == "" {
+				name = pv.Update
+			}
+			central.Versions[strings.ToLower(name)] = expand(pv.version(), props)
+		}
+		// GlobalPackageReference默认为PrivateAssets=All
+		for _, g := range group.GlobalPackageReferences {
+			central.Globals = append(central.Globals, dotnetRequire{
+				Name:    g.Include,
+				Version: expand(g.version(), props),
+				Develop: true,
+			})
+		}
+	}
+	return central
+}
+
+// ReadProject 读取项目文件
+// central: 项目所在目录或上级目录中的Directory.Packages.props 可以为nil
+func ReadProject(file *model.File, central *PackagesProps) *Project {
+
+	proj, props := readMsbuild(file)
+
+	base := path.Base(strings.ReplaceAll(file.Relpath(), `\`, `/`))
+	p := &Project{
+		Name: strings.TrimSuffix(base, path.Ext(base)),
+		File: file,
+	}
+	for _, key := range []string{"PackageId", "AssemblyName"} {
+		if v := expand(props[key], props); v != "" && !strings.Contains(v, "$(") {
+			p.Name = v
+			break
+		}
+	}
+	p.Version = expand(props["Version"], props)
+	for _, key := range []string{"TargetFramework", "TargetFrameworks"} {
+		for _, tfm := range strings.Split(expand(props[key], props), ";") {
+			if tfm = strings.TrimSpace(tfm); tfm != "" {
+				p.Frameworks = append(p.Frameworks, tfm)
+			}
+		}
+	}
+
+	// key:小写组件名
+	index := map[string]int{}
+	for _, group := range proj.ItemGroups {
+		for _, ref := range group.PackageReferences {
+			if ref.Include == "" {
+				// Update仅修改已声明组件的属性
+				if i, ok := index[strings.ToLower(ref.Update)]; ok && ref.version() != "" {
+					p.Requires[i].Version = expand(ref.version(), props)
+				}
+				continue
+			}
+			version := expand(ref.version(), props)
+			if version == "" && central != nil {
+				version = central.Versions[strings.ToLower(ref.Include)]
+			}
+			index[strings.ToLower(ref.Include)] = len(p.Requires)
+			p.Requires = append(p.Requires, dotnetRequire{
+				Name:    ref.Include,
+				Version: version,
+				Develop: ref.private(),
+			})
+		}
+		for _, ref := range group.ProjectReferences {
+			name := path.Base(strings.ReplaceAll(ref.Include, `\`, `/`))
+			p.Requires = append(p.Requires, dotnetRequire{
+				Name:  strings.TrimSuffix(name, path.Ext(name)),
+				Local: true,
+			})
+		}
+	}
+	if central != nil {
+		p.Requires = append(p.Requires, central.Globals...)
+	}
+
+	return p
+}
+
+// dir 项目所在目录
+func (p *Project) dir() string {
+	return path.Dir(strings.ReplaceAll(p.File.Relpath(), `\`, `/`))
+}
+
+// develop 仅用于开发的直接依赖 key:小写组件名
+func (p *Project) develop() map[string]bool {
+	dev := map[string]bool{}
+	if p == nil {
+		return dev
+	}
+	for _, req := range p.Requires {
+		dev[strings.ToLower(req.Name)] = req.Develop
+	}
+	return dev
+}
+
+// ParseProject 解析没有lock的项目文件
+// config: 同目录下的packages.config 可以为nil
+func ParseProject(p *Project, config *model.File) *model.DepGraph {
+	root := &model.DepGraph{Name: p.Name, Version: p.Version, Path: p.File.Relpath()}
+	appendFrameworks(root, p.Frameworks)
+	for _, req := range p.Requires {
+		root.AppendChild(&model.DepGraph{
+			Name:    req.Name,
+			Version: req.Version,
+			Develop: req.Develop,
+			Local:   req.Local,
+		})
+	}
+	if config != nil {
+		for _, c := range ParsePackagesConfig(config).Children {
+			root.AppendChild(c)
+		}
+	}
+	return root
+}
+
+// packagesConfig packages.config
+// https://learn.microsoft.com/nuget/reference/packages-config
+type packagesConfig struct {
+	Packages []struct {
+		Id                    string `xml:"id,attr"`
+		Version               string `xml:"version,attr"`
+		TargetFramework       string `xml:"targetFramework,attr"`
+		DevelopmentDependency bool   `xml:"developmentDependency,attr"`
+	} `xml:"package"`
+}
+
+// ParsePackagesConfig 解析packages.config
+func ParsePackagesConfig(file *model.File) *model.DepGraph {
+	config := &packagesConfig{}
+	file.OpenReader(func(reader io.Reader) {
+		if err := xml.NewDecoder(reader).Decode(config); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+	root := &model.DepGraph{Path: file.Relpath()}
+	var frameworks []string
+	for _, pkg := range config.Packages {
+		root.AppendChild(&model.DepGraph{
+			Name:    pkg.Id,
+			Version: pkg.Version,
+			Develop: pkg.DevelopmentDependency,
+		})
+		frameworks = append(frameworks, pkg.TargetFramework)
+	}
+	appendFrameworks(root, frameworks)
+	return root
+}
+
+// appendFrameworks 添加目标框架对应的运行时组件
+func appendFrameworks(root *model.DepGraph, frameworks []string) {
+	set := map[string]bool{}
+	for _, tfm := range frameworks {
+		name, version := frameworkRuntime(tfm)
+		if version == "" || set[name+version] {
+			continue
+		}
+		set[name+version] = true
+		root.AppendRuntime(name, version)
+	}
+}
+
+var (
+	// .NETCoreApp,Version=v6.0
+	frameworkLongReg = regexp.MustCompile(`^\.(\w+),Version=v([\d.]+)`)
+	// net6.0 netcoreapp3.1 net48 netstandard2.0
+	frameworkShortReg = regexp.MustCompile(`^(netcoreapp|netstandard|net)([\d.]+)`)
+)
+
+// frameworkRuntime 目标框架对应的运行时
+// https://learn.microsoft.com/dotnet/standard/frameworks
+// netstandard为api规范 不对应具体的运行时
+func frameworkRuntime(tfm string) (name, version string) {
+
+	// 去除运行时标识 net6.0/win-x64
+	if i := strings.Index(tfm, "/"); i != -1 {
+		tfm = tfm[:i]
+	}
+	tfm = strings.TrimSpace(tfm)
+
+	if m := frameworkLongReg.FindStringSubmatch(tfm); m != nil {
+		switch strings.ToLower(m[1]) {
+		case "netcoreapp":
+			return model.Runtime_DotNet, m[2]
+		case "netframework":
+			return model.Runtime_DotNetFramework, m[2]
+		}
+		return "", ""
+	}
+
+	m := frameworkShortReg.FindStringSubmatch(strings.ToLower(tfm))
+	if m == nil {
+		return "", ""
+	}
+	switch {
+	case m[1] == "netcoreapp":
+		return model.Runtime_DotNet, m[2]
+	case m[1] == "net" && strings.Contains(m[2], "."):
+		// net5.0及以上
+		return model.Runtime_DotNet, m[2]
+	case m[1] == "net":
+		// .NET Framework net48 => 4.8 net472 => 4.7.2
+		return model.Runtime_DotNetFramework, strings.Join(strings.Split(m[2], ""), ".")
+	}
+	return "", ""
+}
@@ -0,0 +1,97 @@
+package dotnet
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+type Sca struct{}
+
+func (sca Sca) Language() model.Language {
+	return model.Lan_DotNet
+}
+
+func (sca Sca) Filter(relpath string) bool {
+	return filter.DotNetPackagesLock(relpath) ||
+		filter.DotNetProjectAssets(relpath) ||
+		filter.DotNetProject(relpath) ||
+		filter.DotNetPackagesProps(relpath) ||
+		filter.DotNetPackagesConfig(relpath)
+}
+
+func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
+
+	path2dir := func(relpath string) string { return path.Dir(strings.ReplaceAll(relpath, `\`, `/`)) }
+
+	// map[dir]
+	locks := map[string]*model.File{}
+	assets := map[string]*model.File{}
+	configs := map[string]*model.File{}
+	props := map[string]*PackagesProps{}
+	var projects []*model.File
+	for _, f := range files {
+		switch {
+		case filter.DotNetPackagesLock(f.Relpath()):
+			locks[path2dir(f.Relpath())] = f
+		case filter.DotNetProjectAssets(f.Relpath()):
+			// obj/project.assets.json 对应上级目录的项目
+			assets[path.Dir(path2dir(f.Relpath()))] = f
+		case filter.DotNetPackagesConfig(f.Relpath()):
+			configs[path2dir(f.Relpath())] = f
+		case filter.DotNetPackagesProps(f.Relpath()):
+			props[path2dir(f.Relpath())] = ReadPackagesProps(f)
+		case filter.DotNetProject(f.Relpath()):
+			projects = append(projects, f)
+		}
+	}
+
+	for _, f := range projects {
+
+		central, _ := findUp(props, path2dir(f.Relpath()))
+		proj := ReadProject(f, central)
+		dir := proj.dir()
+
+		// 优先使用restore生成的完整依赖图
+		if a, ok := assets[dir]; ok {
+			call(a, ParseProjectAssets(a))
+		} else if lock, ok := locks[dir]; ok {
+			call(lock, ParsePackagesLock(lock, proj))
+		} else {
+			call(f, ParseProject(proj, configs[dir]))
+		}
+		delete(assets, dir)
+		delete(locks, dir)
+		delete(configs, dir)
+	}
+
+	// 没有项目文件的依赖文件
+	for _, f := range assets {
+		call(f, ParseProjectAssets(f))
+	}
+	for _, f := range locks {
+		call(f, ParsePackagesLock(f, nil))
+	}
+	for _, f := range configs {
+		call(f, ParsePackagesConfig(f))
+	}
+}
+
+// findUp 从dir开始逐级向上查找
+func findUp[T any](m map[string]T, dir string) (T, bool) {
+	for {
+		if v, ok := m[dir]; ok {
+			return v, true
+		}
+		parent := path.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	var zero T
+	return zero, false
+}
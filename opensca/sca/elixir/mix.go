@@ -0,0 +1,274 @@
+package elixir
+
+import (
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/erlang"
+)
+
+// mixRequire mix.exs中声明的依赖
+type mixRequire struct {
+	Name    string
+	Version string
+	// only: [:dev, :test]
+	Develop bool
+	// path/in_umbrella引用的本地应用
+	Local bool
+}
+
+// MixExs mix.exs
+// https://hexdocs.pm/mix/Mix.Tasks.Deps.html
+type MixExs struct {
+	App     string
+	Version string
+	// umbrella项目的应用目录
+	AppsPath string
+	Requires []mixRequire
+	File     *model.File
+}
+
+var (
+	mixAppReg       = regexp.MustCompile(`\bapp:\s*:(\w+)`)
+	mixVersionReg   = regexp.MustCompile(`\bversion:\s*(?:"([^"]*)"|@(\w+))`)
+	mixAttrReg      = regexp.MustCompile(`(?m)^\s*@(\w+)\s+"([^"]*)"`)
+	mixAppsPathReg  = regexp.MustCompile(`\bapps_path:\s*"([^"]*)"`)
+	mixDepsReg      = regexp.MustCompile(`(?s)\bdefp?\s+deps\b[^\n]*?(?:\bdo:\s*\[(.*?)\]\s*\n|\bdo\b(.*?)\n\s*end\b)`)
+	mixDepReg       = regexp.MustCompile(`\{\s*:(\w+)\s*(?:,\s*"([^"]*)")?([^{}]*)\}`)
+	mixOnlyReg      = regexp.MustCompile(`\bonly:\s*(:\w+|\[[^\]]*\])`)
+	mixLocalReg     = regexp.MustCompile(`\b(path:|in_umbrella:\s*true)`)
+	mixOnlyAtomsReg = regexp.MustCompile(`:(\w+)`)
+)
+
+// ReadMixExs 读取mix.exs中的项目信息及依赖
+func ReadMixExs(file *model.File) *MixExs {
+
+	exs := &MixExs{File: file}
+
+	file.OpenReader(func(reader io.Reader) {
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			logs.Warn(err)
+			return
+		}
+		text := string(data)
+
+		attrs := map[string]string{}
+		for _, m := range mixAttrReg.FindAllStringSubmatch(text, -1) {
+			attrs[m[1]] = m[2]
+		}
+
+		if m := mixAppReg.FindStringSubmatch(text); m != nil {
+			exs.App = m[1]
+		}
+		if m := mixVersionReg.FindStringSubmatch(text); m != nil {
+			exs.Version = m[1]
+			if m[2] != "" {
+				exs.Version = attrs[m[2]]
+			}
+		}
+		if m := mixAppsPathReg.FindStringSubmatch(text); m != nil {
+			exs.AppsPath = m[1]
+		}
+
+		m := mixDepsReg.FindStringSubmatch(text)
+		if m == nil {
+			return
+		}
+		for _, dep := range mixDepReg.FindAllStringSubmatch(m[1]+m[2], -1) {
+			req := mixRequire{Name: dep[1], Version: dep[2]}
+			if only := mixOnlyReg.FindStringSubmatch(dep[3]); only != nil {
+				req.Develop = true
+				for _, env := range mixOnlyAtomsReg.FindAllStringSubmatch(only[1], -1) {
+					if env[1] != "dev" && env[1] != "test" {
+						req.Develop = false
+					}
+				}
+			}
+			req.Local = mixLocalReg.MatchString(dep[3])
+			exs.Requires = append(exs.Requires, req)
+		}
+	})
+
+	return exs
+}
+
+// dir mix.exs所在目录
+func (exs *MixExs) dir() string {
+	return path.Dir(strings.ReplaceAll(exs.File.Relpath(), `\`, `/`))
+}
+
+// ParseMixExs 解析没有mix.lock的mix.exs
+func ParseMixExs(exs *MixExs) *model.DepGraph {
+	root := &model.DepGraph{Name: exs.App, Version: exs.Version, Path: exs.File.Relpath()}
+	for _, req := range exs.Requires {
+		root.AppendChild(&model.DepGraph{
+			Name:    req.Name,
+			Version: req.Version,
+			Develop: req.Develop,
+			Local:   req.Local,
+		})
+	}
+	return root
+}
+
+// ParseMixLock 解析mix.lock
+// %{"plug": {:hex, :plug, "1.14.0", "inner", [:mix], [{:mime, "~> 2.0", [hex: :mime, repo: "hexpm", optional: false]}], "hexpm", "outer"},
+//
+//	"phoenix": {:git, "https://github.com/phoenixframework/phoenix.git", "sha", [tag: "v1.7.0"]}}
+//
+// exs: 同目录下的mix.exs 可以为nil
+// apps: umbrella项目中的应用
+func ParseMixLock(file *model.File, exs *MixExs, apps []*MixExs) *model.DepGraph {
+
+	var lock map[string]any
+	file.OpenReader(func(reader io.Reader) {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			logs.Warn(err)
+			return
+		}
+		term, err := erlang.ReadElixirTerm(data)
+		if err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+			return
+		}
+		lock, _ = term.(map[string]any)
+	})
+
+	_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph {
+		return &model.DepGraph{Name: s[0]}
+	}).LoadOrStore
+
+	// 锁定的组件 key:应用名
+	locked := map[string]*model.DepGraph{}
+	names := make([]string, 0, len(lock))
+	for name := range lock {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// 第一次遍历记录组件信息
+	for _, name := range names {
+		t, ok := lock[name].(erlang.Tuple)
+		if !ok || len(t) < 3 {
+			continue
+		}
+		dep := _dep(name)
+		switch t[0] {
+		case erlang.Atom("hex"):
+			// hex组件名可能与应用名不同
+			if pkg, ok := t[1].(erlang.Atom); ok {
+				dep.Name = string(pkg)
+			}
+			dep.Version, _ = t[2].(string)
+			if len(t) > 7 {
+				dep.Integrity, _ = t[7].(string)
+			} else if len(t) > 3 {
+				dep.Integrity, _ = t[3].(string)
+			}
+		default:
+			// git仓库使用tag或提交作为版本
+			dep.Version, _ = t[2].(string)
+			if len(t) > 3 {
+				opts, _ := t[3].(erlang.List)
+				for _, opt := range opts {
+					if kv, ok := opt.(erlang.Tuple); ok && len(kv) == 2 && kv[0] == erlang.Atom("tag") {
+						if tag, ok := kv[1].(string); ok {
+							dep.Version = tag
+						}
+					}
+				}
+			}
+		}
+		locked[name] = dep
+	}
+
+	// 第二次遍历记录依赖关系
+	for _, name := range names {
+		t, ok := lock[name].(erlang.Tuple)
+		if !ok || len(t) < 6 || t[0] != erlang.Atom("hex") {
+			continue
+		}
+		reqs, _ := t[5].(erlang.List)
+		for _, req := range reqs {
+			r, ok := req.(erlang.Tuple)
+			if !ok || len(r) == 0 {
+				continue
+			}
+			app, _ := r[0].(erlang.Atom)
+			// 未启用的可选依赖不在lock中
+			if sub, ok := locked[string(app)]; ok {
+				locked[name].AppendChild(sub)
+			}
+		}
+	}
+
+	root := &model.DepGraph{Path: file.Relpath()}
+
+	// 仅用于开发环境的直接依赖 多处声明时全部为开发依赖才视为开发依赖
+	devReq := map[*model.DepGraph]bool{}
+	// umbrella项目中的应用 key:应用名
+	appMap := map[string]*model.DepGraph{}
+	for _, app := range apps {
+		appMap[app.App] = &model.DepGraph{Name: app.App, Version: app.Version, Local: true}
+	}
+	appendRequires := func(parent *model.DepGraph, exs *MixExs) {
+		for _, req := range exs.Requires {
+			dep, ok := locked[req.Name]
+			if !ok {
+				if !req.Local {
+					continue
+				}
+				if dep, ok = appMap[req.Name]; !ok {
+					dep = &model.DepGraph{Name: req.Name, Version: req.Version, Local: true}
+				}
+			}
+			if d, ok := devReq[dep]; ok {
+				devReq[dep] = d && req.Develop
+			} else {
+				devReq[dep] = req.Develop
+			}
+			parent.AppendChild(dep)
+		}
+	}
+	if exs != nil {
+		root.Name = exs.App
+		root.Version = exs.Version
+		appendRequires(root, exs)
+	}
+	for _, app := range apps {
+		dep := appMap[app.App]
+		appendRequires(dep, app)
+		root.AppendChild(dep)
+	}
+
+	// 仅被开发依赖引用的组件为开发组件
+	prod := map[*model.DepGraph]bool{}
+	root.ForEachNode(func(p, n *model.DepGraph) bool {
+		if devReq[n] {
+			return false
+		}
+		prod[n] = true
+		return true
+	})
+	root.ForEachNode(func(p, n *model.DepGraph) bool {
+		n.Develop = !prod[n]
+		return true
+	})
+
+	// 没有被引用的组件
+	for _, name := range names {
+		if dep, ok := locked[name]; ok && len(dep.Parents) == 0 {
+			root.AppendChild(dep)
+		}
+	}
+
+	return root
+}
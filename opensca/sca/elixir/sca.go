@@ -0,0 +1,81 @@
+package elixir
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+type Sca struct{}
+
+func (sca Sca) Language() model.Language {
+	return model.Lan_Elixir
+}
+
+func (sca Sca) Filter(relpath string) bool {
+	return filter.ElixirMixLock(relpath) || filter.ElixirMixExs(relpath)
+}
+
+func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
+
+	path2dir := func(relpath string) string { return path.Dir(strings.ReplaceAll(relpath, `\`, `/`)) }
+
+	// map[dir]
+	locks := map[string]*model.File{}
+	exsMap := map[string]*MixExs{}
+	for _, f := range files {
+		// deps中为已下载的依赖源码
+		if inDeps(f.Relpath()) {
+			continue
+		}
+		switch {
+		case filter.ElixirMixLock(f.Relpath()):
+			locks[path2dir(f.Relpath())] = f
+		case filter.ElixirMixExs(f.Relpath()):
+			exs := ReadMixExs(f)
+			exsMap[exs.dir()] = exs
+		}
+	}
+
+	// umbrella项目中的应用 key:umbrella项目目录
+	umbrella := map[string][]*MixExs{}
+	used := map[*MixExs]bool{}
+	for dir, exs := range exsMap {
+		if exs.AppsPath == "" {
+			continue
+		}
+		appsDir := path.Join(dir, exs.AppsPath)
+		for appDir, app := range exsMap {
+			if path.Dir(appDir) == appsDir {
+				umbrella[dir] = append(umbrella[dir], app)
+				used[app] = true
+			}
+		}
+	}
+
+	for dir, f := range locks {
+		exs := exsMap[dir]
+		used[exs] = true
+		call(f, ParseMixLock(f, exs, umbrella[dir]))
+	}
+
+	// 没有mix.lock的mix.exs
+	for _, exs := range exsMap {
+		if !used[exs] {
+			call(exs.File, ParseMixExs(exs))
+		}
+	}
+}
+
+// inDeps 是否为deps目录中的文件
+func inDeps(relpath string) bool {
+	for _, dir := range strings.Split(path.Dir(strings.ReplaceAll(relpath, `\`, `/`)), "/") {
+		if dir == "deps" {
+			return true
+		}
+	}
+	return false
+}
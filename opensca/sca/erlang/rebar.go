@@ -0,0 +1,139 @@
+package erlang
+
+import (
+	"io"
+	"sort"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// rebarLock rebar.lock中的组件
+type rebarLock struct {
+	// 应用名
+	App string
+	// 组件名 hex组件名可能与应用名不同
+	Name    string
+	Version string
+	// 依赖层级 0为直接依赖
+	Level int
+}
+
+// ParseRebarLock 解析rebar.lock
+// 1.2.0格式:
+//
+//	{"1.2.0", [{<<"name">>, {pkg, <<"name">>, <<"1.0.0">>}, 0}, {<<"name">>, {git, "url", {ref, "sha"}}, 1}]}.
+//	[{pkg_hash, [{<<"name">>, <<"HASH">>}]}, {pkg_hash_ext, [...]}].
+//
+// 旧格式仅包含组件列表
+// rebar.lock只记录组件的依赖层级 不记录依赖关系 间接依赖统一挂载在根节点下并标记为间接依赖
+func ParseRebarLock(file *model.File) *model.DepGraph {
+
+	var terms []any
+	file.OpenReader(func(reader io.Reader) {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			logs.Warn(err)
+			return
+		}
+		if terms, err = ReadTerms(data); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	root := &model.DepGraph{Path: file.Relpath()}
+	if len(terms) == 0 {
+		return root
+	}
+
+	// 组件列表
+	var entries List
+	switch t := terms[0].(type) {
+	case Tuple:
+		if len(t) == 2 {
+			entries, _ = t[1].(List)
+		}
+	case List:
+		entries = t
+	}
+
+	// 组件哈希 key:组件名 优先使用pkg_hash_ext(sha256)
+	hashes := map[string]string{}
+	if len(terms) > 1 {
+		if l, ok := terms[1].(List); ok {
+			for _, kind := range []Atom{"pkg_hash", "pkg_hash_ext"} {
+				for _, item := range l {
+					t, ok := item.(Tuple)
+					if !ok || len(t) != 2 || t[0] != kind {
+						continue
+					}
+					hs, _ := t[1].(List)
+					for _, h := range hs {
+						if kv, ok := h.(Tuple); ok && len(kv) == 2 {
+							name, _ := kv[0].(string)
+							hash, _ := kv[1].(string)
+							hashes[name] = hash
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var locks []rebarLock
+	for _, e := range entries {
+		if lock, ok := readRebarLock(e); ok {
+			locks = append(locks, lock)
+		}
+	}
+	sort.SliceStable(locks, func(i, j int) bool { return locks[i].Level < locks[j].Level })
+
+	for _, lock := range locks {
+		root.AppendChild(&model.DepGraph{
+			Name:      lock.Name,
+			Version:   lock.Version,
+			Integrity: hashes[lock.App],
+			// 层级大于0的组件为间接依赖
+			Transitive: lock.Level > 0,
+		})
+	}
+
+	return root
+}
+
+// readRebarLock 读取 {Name, Source, Level}
+// Source: {pkg, PkgName, Version[, Hash[, Repo]]} | {git|hg, Url, {ref|tag|branch, Rev}} | {git_subdir, Url, {ref, Rev}, Dir}
+func readRebarLock(term any) (lock rebarLock, ok bool) {
+
+	t, ok := term.(Tuple)
+	if !ok || len(t) < 2 {
+		return lock, false
+	}
+	if lock.App, ok = t[0].(string); !ok {
+		return lock, false
+	}
+	lock.Name = lock.App
+	if len(t) > 2 {
+		lock.Level, _ = t[2].(int)
+	}
+
+	src, ok := t[1].(Tuple)
+	if !ok || len(src) < 3 {
+		return lock, false
+	}
+	switch src[0] {
+	case Atom("pkg"):
+		if name, _ := src[1].(string); name != "" {
+			lock.Name = name
+		}
+		lock.Version, _ = src[2].(string)
+	default:
+		// 代码仓库 使用tag/ref/branch作为版本
+		if rev, ok := src[2].(Tuple); ok && len(rev) == 2 {
+			lock.Version, _ = rev[1].(string)
+		} else {
+			lock.Version, _ = src[2].(string)
+		}
+	}
+	return lock, true
+}
@@ -2,7 +2,6 @@ package erlang
 
 import (
 	"context"
-	"regexp"
 
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
@@ -25,18 +24,3 @@ func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File,
 		}
 	}
 }
-
-func ParseRebarLock(file *model.File) *model.DepGraph {
-	reg := regexp.MustCompile(`<<"([\w\d]+)">>\S*?pkg,<<"[\w\d]+">>,<<"([.\d]+)">>`)
-	root := &model.DepGraph{Path: file.Relpath()}
-	file.ReadLine(func(line string) {
-		match := reg.FindStringSubmatch(line)
-		if len(match) > 2 {
-			root.AppendChild(&model.DepGraph{
-				Name:    match[1],
-				Version: match[2],
-			})
-		}
-	})
-	return root
-}
@@ -0,0 +1,412 @@
+package erlang
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Atom 原子 如 pkg git true
+type Atom string
+
+// Tuple 元组 {a, b}
+type Tuple []any
+
+// List 列表 [a, b] map #{k => v} 读取为由{k, v}元组组成的列表
+type List []any
+
+// 字符串及二进制(<<"x">>)读取为string 整数读取为int 浮点数读取为float64
+//
+// elixir字面量:
+// :hex :"quoted" 及 true/false/nil 读取为Atom
+// 关键字列表[hex: :plug]中的元素读取为{Atom, value}元组
+// map %{"key": value, "key" => value} 读取为map[string]any
+
+// termReader erlang term及elixir字面量读取器
+// https://www.erlang.org/doc/reference_manual/data_types
+type termReader struct {
+	data []byte
+	pos  int
+	// 按elixir语法读取
+	elixir bool
+}
+
+// ReadElixirTerm 读取单个elixir字面量 如mix.lock
+func ReadElixirTerm(data []byte) (any, error) {
+	r := &termReader{data: data, elixir: true}
+	return r.term()
+}
+
+// ReadTerms 读取以.结尾的全部term 如rebar.lock、rebar.config
+func ReadTerms(data []byte) ([]any, error) {
+	r := &termReader{data: data}
+	var terms []any
+	for {
+		r.skip()
+		if r.pos >= len(r.data) {
+			return terms, nil
+		}
+		term, err := r.term()
+		if err != nil {
+			return terms, err
+		}
+		terms = append(terms, term)
+		r.skip()
+		if !r.consume(".") {
+			return terms, r.errorf("expect .")
+		}
+	}
+}
+
+func (r *termReader) errorf(format string, args ...any) error {
+	line := bytes.Count(r.data[:r.pos], []byte("\n")) + 1
+	lang := "erlang"
+	if r.elixir {
+		lang = "elixir"
+	}
+	return fmt.Errorf("%s term line %d: %s", lang, line, fmt.Sprintf(format, args...))
+}
+
+// skip 跳过空白及注释
+func (r *termReader) skip() {
+	for r.pos < len(r.data) {
+		switch c := r.data[r.pos]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			r.pos++
+		case c == '%' && !r.elixir || c == '#' && r.elixir:
+			for r.pos < len(r.data) && r.data[r.pos] != '\n' {
+				r.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// consume 跳过空白后读取指定字符串
+func (r *termReader) consume(s string) bool {
+	r.skip()
+	if bytes.HasPrefix(r.data[r.pos:], []byte(s)) {
+		r.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (r *termReader) term() (any, error) {
+	r.skip()
+	if r.pos >= len(r.data) {
+		return nil, r.errorf("unexpected end")
+	}
+	if r.elixir {
+		return r.elixirTerm()
+	}
+	switch c := r.data[r.pos]; {
+	case c == '{':
+		r.pos++
+		items, err := r.items("}")
+		return Tuple(items), err
+	case c == '[':
+		r.pos++
+		items, err := r.items("]")
+		return List(items), err
+	case c == '#':
+		return r.mapTerm()
+	case c == '<':
+		return r.binary()
+	case c == '"':
+		return r.quoted('"')
+	case c == '\'':
+		s, err := r.quoted('\'')
+		return Atom(s), err
+	case c == '-' || c >= '0' && c <= '9':
+		return r.number()
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_':
+		start := r.pos
+		for r.pos < len(r.data) && isAtomChar(r.data[r.pos]) {
+			r.pos++
+		}
+		return Atom(r.data[start:r.pos]), nil
+	default:
+		return nil, r.errorf("unexpected %q", c)
+	}
+}
+
+func (r *termReader) elixirTerm() (any, error) {
+	switch c := r.data[r.pos]; {
+	case c == '%':
+		return r.elixirMap()
+	case c == '{':
+		r.pos++
+		items, err := r.items("}")
+		return Tuple(items), err
+	case c == '[':
+		r.pos++
+		items, err := r.items("]")
+		return List(items), err
+	case c == '"':
+		return r.quoted('"')
+	case c == ':':
+		r.pos++
+		if r.pos < len(r.data) && r.data[r.pos] == '"' {
+			s, err := r.quoted('"')
+			return Atom(s), err
+		}
+		return Atom(r.word()), nil
+	case c == '-' || c >= '0' && c <= '9':
+		return r.number()
+	case r.isWordChar(c):
+		return Atom(r.word()), nil
+	default:
+		return nil, r.errorf("unexpected %q", c)
+	}
+}
+
+func isAtomChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '@'
+}
+
+// isWordChar elixir中原子及关键字可以包含.?!
+func (r *termReader) isWordChar(c byte) bool {
+	if !r.elixir {
+		return isAtomChar(c)
+	}
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '?' || c == '!'
+}
+
+func (r *termReader) word() string {
+	start := r.pos
+	for r.pos < len(r.data) && r.isWordChar(r.data[r.pos]) {
+		r.pos++
+	}
+	return string(r.data[start:r.pos])
+}
+
+// keyword 读取elixir关键字 key: 不是关键字时回退
+func (r *termReader) keyword() (string, bool) {
+	if !r.elixir {
+		return "", false
+	}
+	r.skip()
+	start := r.pos
+	var key string
+	if r.pos < len(r.data) && r.data[r.pos] == '"' {
+		s, err := r.quoted('"')
+		if err != nil {
+			r.pos = start
+			return "", false
+		}
+		key = s
+	} else {
+		key = r.word()
+	}
+	if key != "" && r.pos+1 < len(r.data) && r.data[r.pos] == ':' && r.data[r.pos+1] != ':' {
+		r.pos++
+		return key, true
+	}
+	r.pos = start
+	return "", false
+}
+
+// items 读取以逗号分隔的元素直到end 允许末尾的逗号
+func (r *termReader) items(end string) ([]any, error) {
+	items := []any{}
+	for {
+		if r.consume(end) {
+			return items, nil
+		}
+		key, keyword := r.keyword()
+		item, err := r.term()
+		if err != nil {
+			return items, err
+		}
+		if keyword {
+			item = Tuple{Atom(key), item}
+		}
+		items = append(items, item)
+		if r.consume(",") {
+			continue
+		}
+		if r.consume(end) {
+			return items, nil
+		}
+		return items, r.errorf("expect , or %s", end)
+	}
+}
+
+// mapTerm #{k => v}
+func (r *termReader) mapTerm() (any, error) {
+	if !r.consume("#{") {
+		return nil, r.errorf("expect #{")
+	}
+	m := List{}
+	if r.consume("}") {
+		return m, nil
+	}
+	for {
+		k, err := r.term()
+		if err != nil {
+			return m, err
+		}
+		if !r.consume("=>") && !r.consume(":=") {
+			return m, r.errorf("expect =>")
+		}
+		v, err := r.term()
+		if err != nil {
+			return m, err
+		}
+		m = append(m, Tuple{k, v})
+		if r.consume(",") {
+			continue
+		}
+		if r.consume("}") {
+			return m, nil
+		}
+		return m, r.errorf("expect , or }")
+	}
+}
+
+// elixirMap %{"key": value, "key" => value}
+func (r *termReader) elixirMap() (any, error) {
+	if !r.consume("%{") {
+		return nil, r.errorf("expect %%{")
+	}
+	m := map[string]any{}
+	for {
+		if r.consume("}") {
+			return m, nil
+		}
+		key, ok := r.keyword()
+		if !ok {
+			k, err := r.term()
+			if err != nil {
+				return m, err
+			}
+			if !r.consume("=>") {
+				return m, r.errorf("expect =>")
+			}
+			key = fmt.Sprint(k)
+		}
+		v, err := r.term()
+		if err != nil {
+			return m, err
+		}
+		m[key] = v
+		if r.consume(",") {
+			continue
+		}
+		if r.consume("}") {
+			return m, nil
+		}
+		return m, r.errorf("expect , or }")
+	}
+}
+
+// binary <<"abc">> <<"abc"/utf8>> <<97,98>>
+func (r *termReader) binary() (any, error) {
+	if !r.consume("<<") {
+		return nil, r.errorf("expect <<")
+	}
+	sb := strings.Builder{}
+	if r.consume(">>") {
+		return "", nil
+	}
+	for {
+		seg, err := r.term()
+		if err != nil {
+			return nil, err
+		}
+		switch v := seg.(type) {
+		case string:
+			sb.WriteString(v)
+		case int:
+			sb.WriteByte(byte(v))
+		}
+		// 忽略类型说明 如 /utf8
+		if r.consume("/") {
+			if _, err := r.term(); err != nil {
+				return nil, err
+			}
+		}
+		if r.consume(",") {
+			continue
+		}
+		if r.consume(">>") {
+			return sb.String(), nil
+		}
+		return nil, r.errorf("expect , or >>")
+	}
+}
+
+// quoted 读取引号包裹的字符串或原子
+func (r *termReader) quoted(quote byte) (string, error) {
+	r.pos++
+	sb := strings.Builder{}
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		r.pos++
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if r.pos >= len(r.data) {
+				break
+			}
+			e := r.data[r.pos]
+			r.pos++
+			switch e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", r.errorf("unterminated string")
+}
+
+func (r *termReader) number() (any, error) {
+	start := r.pos
+	if r.data[r.pos] == '-' {
+		r.pos++
+	}
+	float := false
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		if c >= '0' && c <= '9' || c == '_' || c == '#' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			r.pos++
+		} else if c == '.' && r.pos+1 < len(r.data) && r.data[r.pos+1] >= '0' && r.data[r.pos+1] <= '9' {
+			float = true
+			r.pos++
+		} else if (c == '+' || c == '-') && float && (r.data[r.pos-1] == 'e' || r.data[r.pos-1] == 'E') {
+			r.pos++
+		} else {
+			break
+		}
+	}
+	s := strings.ReplaceAll(string(r.data[start:r.pos]), "_", "")
+	if float {
+		return strconv.ParseFloat(s, 64)
+	}
+	// 16#FF 进制表示
+	if i := strings.Index(s, "#"); i != -1 {
+		base, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return nil, r.errorf("invalid number %s", s)
+		}
+		n, err := strconv.ParseInt(s[i+1:], base, 64)
+		return int(n), err
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, r.errorf("invalid number %s", s)
+	}
+	return n, nil
+}
@@ -1,6 +1,9 @@
 package filter
 
 import (
+	"bytes"
+	"encoding/binary"
+	"io"
 	"path/filepath"
 	"strings"
 )
@@ -17,44 +20,137 @@ func filterFunc(strFunc func(string, string) bool, args ...string) func(string)
 }
 
 var (
-	JavaPom = filterFunc(strings.HasSuffix, "pom.xml", ".pom")
+	JavaPom           = filterFunc(strings.HasSuffix, "pom.xml", ".pom")
+	JavaMvnExtensions = func(filename string) bool {
+		return filepath.Base(filename) == "extensions.xml" && filepath.Base(filepath.Dir(filename)) == ".mvn"
+	}
+	JavaIvy         = filterFunc(strings.HasSuffix, "ivy.xml")
+	JavaIvySettings = filterFunc(strings.HasSuffix, "ivysettings.xml")
+	JavaSbt         = filterFunc(strings.HasSuffix, ".sbt")
+	JavaSbtLock     = filterFunc(strings.HasSuffix, "build.sbt.lock")
+	// 记录sbt版本的project/build.properties
+	JavaSbtProperties = filterFunc(strings.HasSuffix, "project/build.properties", `project\build.properties`)
 )
 
 var (
-	JavaScriptPackageLock = filterFunc(strings.HasSuffix, "package-lock.json")
+	JavaScriptPackageLock = filterFunc(strings.HasSuffix, "package-lock.json", "npm-shrinkwrap.json")
+	JavaScriptShrinkwrap  = filterFunc(strings.HasSuffix, "npm-shrinkwrap.json")
 	JavaScriptPackageJson = func(filename string) bool {
 		return strings.HasSuffix(filename, "package.json")
 	}
 	JavaScriptYarnLock = filterFunc(strings.HasSuffix, "yarn.lock")
+	JavaScriptPnpmLock = filterFunc(strings.HasSuffix, "pnpm-lock.yaml")
+	// 静态资源中的js文件 node_modules中的组件由package.json识别
+	JavaScriptFile = func(filename string) bool {
+		return filterFunc(strings.HasSuffix, ".js", ".mjs", ".cjs")(filename) &&
+			!strings.Contains(strings.ReplaceAll(filename, `\`, "/"), "node_modules/")
+	}
 )
 
 var (
 	PhpComposer     = filterFunc(strings.HasSuffix, "composer.json")
 	PhpComposerLock = filterFunc(strings.HasSuffix, "composer.lock")
+	// composer安装后生成的vendor/composer/installed.json
+	PhpComposerInstalled = filterFunc(strings.HasSuffix, "composer/installed.json", `composer\installed.json`)
 )
 
 var (
 	RubyGemfileLock = filterFunc(strings.HasSuffix, "Gemfile.lock", "gems.locked")
+	RubyGemfile     = filterFunc(strings.HasSuffix, "Gemfile", "gems.rb")
+	RubyGemspec     = filterFunc(strings.HasSuffix, ".gemspec")
 )
 
 var (
 	GoMod     = filterFunc(strings.HasSuffix, "go.mod")
 	GoSum     = filterFunc(strings.HasSuffix, "go.sum", "go.work.sum")
+	GoWork    = filterFunc(strings.HasSuffix, "go.work")
+	GoVendor  = filterFunc(strings.HasSuffix, "vendor/modules.txt", `vendor\modules.txt`)
 	GoPkgToml = filterFunc(strings.HasSuffix, "Gopkg.toml")
 	GoPkgLock = filterFunc(strings.HasSuffix, "Gopkg.lock")
 )
 
 var (
 	RustCargoLock = filterFunc(strings.HasSuffix, "Cargo.lock")
+	RustCargoToml = filterFunc(strings.HasSuffix, "Cargo.toml")
 )
 
+// 常见的无扩展名文本文件
+var textFiles = map[string]bool{
+	"license": true, "licence": true, "copying": true, "notice": true, "authors": true,
+	"readme": true, "changelog": true, "makefile": true, "dockerfile": true, "jenkinsfile": true,
+	"vagrantfile": true, "procfile": true, "gemfile": true, "rakefile": true, "podfile": true,
+	"pipfile": true, "brewfile": true, "version": true, "codeowners": true,
+}
+
+// Executable 可能为可执行文件(ELF/PE/Mach-O) 如go或cargo-auditable构建的rust可执行文件
+// 需要再通过ExecutableMagic确认文件内容
+func Executable(filename string) bool {
+	base := filepath.Base(strings.ReplaceAll(filename, `\`, `/`))
+	if strings.HasPrefix(base, ".") || textFiles[strings.ToLower(base)] {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(base))
+	return ext == "" || ext == ".exe" || ext == ".bin" || ext == ".out"
+}
+
+// ExecutableMagic 读取文件头判断是否为ELF/PE/Mach-O可执行文件
+func ExecutableMagic(reader io.Reader) bool {
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(reader, magic); err != nil {
+		return false
+	}
+	switch {
+	// ELF
+	case bytes.Equal(magic, []byte{0x7f, 'E', 'L', 'F'}):
+		return true
+	// PE
+	case magic[0] == 'M' && magic[1] == 'Z':
+		return true
+	}
+	// Mach-O 32/64位及大小端 以及fat二进制
+	switch binary.BigEndian.Uint32(magic) {
+	case 0xfeedface, 0xcefaedfe, 0xfeedfacf, 0xcffaedfe, 0xcafebabe:
+		return true
+	}
+	return false
+}
+
 var (
 	ErlangRebarLock = filterFunc(strings.HasSuffix, "rebar.lock")
 )
 
 var (
-	GroovyFile   = filterFunc(strings.HasSuffix, ".groovy")
-	GroovyGradle = filterFunc(strings.HasSuffix, ".gradle", ".gradle.kts")
+	ElixirMixLock = filterFunc(strings.HasSuffix, "mix.lock")
+	ElixirMixExs  = filterFunc(strings.HasSuffix, "mix.exs")
+)
+
+var (
+	DotNetPackagesLock   = filterFunc(strings.HasSuffix, "packages.lock.json")
+	DotNetProjectAssets  = filterFunc(strings.HasSuffix, "project.assets.json")
+	DotNetProject        = filterFunc(strings.HasSuffix, ".csproj", ".fsproj", ".vbproj")
+	DotNetPackagesProps  = filterFunc(strings.HasSuffix, "Directory.Packages.props")
+	DotNetPackagesConfig = filterFunc(strings.HasSuffix, "packages.config")
+)
+
+var (
+	SwiftPackageResolved = filterFunc(strings.HasSuffix, "Package.resolved")
+)
+
+var (
+	CocoaPodsPodfileLock = filterFunc(strings.HasSuffix, "Podfile.lock")
+)
+
+var (
+	DartPubspecLock = filterFunc(strings.HasSuffix, "pubspec.lock")
+	DartPubspecYaml = filterFunc(strings.HasSuffix, "pubspec.yaml")
+)
+
+var (
+	GroovyFile           = filterFunc(strings.HasSuffix, ".groovy")
+	GroovyGradle         = filterFunc(strings.HasSuffix, ".gradle", ".gradle.kts")
+	GroovyGradleSettings = filterFunc(strings.HasSuffix, "settings.gradle", "settings.gradle.kts")
+	GroovyGradleLock     = filterFunc(strings.HasSuffix, ".lockfile")
+	GroovyVersionCatalog = filterFunc(strings.HasSuffix, ".versions.toml")
 )
 
 var (
@@ -66,6 +162,26 @@ var (
 			filterFunc(strings.Contains, "requirements")(filepath.Base(filename)) && !filterFunc(strings.Contains, "test")(filepath.Base(filename))
 	}
 	PythonRequirementsIn = filterFunc(strings.HasSuffix, "requirements.in")
+	PythonPyProject      = filterFunc(strings.HasSuffix, "pyproject.toml")
+	PythonPoetryLock     = filterFunc(strings.HasSuffix, "poetry.lock")
+	PythonPdmLock        = filterFunc(strings.HasSuffix, "pdm.lock")
+	PythonUvLock         = filterFunc(strings.HasSuffix, "uv.lock")
+	// 已安装组件的元数据 *.dist-info/METADATA *.egg-info/PKG-INFO EGG-INFO/PKG-INFO *.egg-info
+	PythonDistMetadata = func(filename string) bool {
+		base, dir := filepath.Base(filename), filepath.Dir(filename)
+		switch base {
+		case "METADATA":
+			return strings.HasSuffix(dir, ".dist-info")
+		case "PKG-INFO":
+			return strings.HasSuffix(dir, ".egg-info") || filepath.Base(dir) == "EGG-INFO"
+		}
+		return strings.HasSuffix(filename, ".egg-info")
+	}
+	PythonDistRequires = func(filename string) bool {
+		dir := filepath.Dir(filename)
+		return filepath.Base(filename) == "requires.txt" && (strings.HasSuffix(dir, ".egg-info") || filepath.Base(dir) == "EGG-INFO")
+	}
+	PythonWheel = filterFunc(strings.HasSuffix, ".whl", ".egg")
 )
 
 var (
@@ -86,5 +202,7 @@ var (
 		".tar",
 		".gz",
 		".bz2",
+		".whl",
+		".egg",
 	)
 )
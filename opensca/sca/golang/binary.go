@@ -0,0 +1,76 @@
+package golang
+
+import (
+	"debug/buildinfo"
+	"io"
+	"runtime/debug"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+// ParseGoBinary 解析go可执行文件(ELF/PE/Mach-O)中嵌入的模块信息
+// 非go可执行文件返回nil
+func ParseGoBinary(file *model.File) *model.DepGraph {
+
+	executable := false
+	file.OpenReader(func(reader io.Reader) {
+		executable = filter.ExecutableMagic(reader)
+	})
+	if !executable {
+		return nil
+	}
+
+	info, err := buildinfo.ReadFile(file.Abspath())
+	if err != nil {
+		return nil
+	}
+
+	root := &model.DepGraph{
+		Name:    info.Main.Path,
+		Version: goBinaryVersion(info.Main.Version),
+		Path:    file.Relpath(),
+	}
+	// 未使用module构建时仅记录了main包路径
+	if root.Name == "" {
+		root.Name = info.Path
+	}
+
+	for _, mod := range info.Deps {
+		root.AppendChild(goBinaryDep(mod))
+	}
+
+	// go标准库 版本为构建时使用的go工具链版本
+	if strings.HasPrefix(info.GoVersion, "go") {
+		version := strings.Fields(strings.TrimPrefix(info.GoVersion, "go"))[0]
+		root.AppendRuntime(model.Runtime_Go, version)
+	}
+
+	return root
+}
+
+// goBinaryDep 嵌入的依赖模块 使用replace替换后的模块
+func goBinaryDep(mod *debug.Module) *model.DepGraph {
+
+	if mod.Replace == nil {
+		dep := newGoDep(mod.Path, goBinaryVersion(mod.Version), nil)
+		dep.Integrity = mod.Sum
+		return dep
+	}
+
+	r := &goReplace{Old: mod.Path, OldVersion: mod.Version, New: mod.Replace.Path, NewVersion: goBinaryVersion(mod.Replace.Version)}
+	dep := newGoDep(mod.Path, goBinaryVersion(mod.Version), r)
+	if !dep.Local {
+		dep.Integrity = mod.Replace.Sum
+	}
+	return dep
+}
+
+// goBinaryVersion 模块版本 本地构建的版本为(devel)
+func goBinaryVersion(version string) string {
+	if version == "(devel)" {
+		return ""
+	}
+	return strings.TrimSuffix(version, "+incompatible")
+}
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"os/exec"
 	"path/filepath"
 	"sort"
@@ -12,113 +14,309 @@ import (
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 )
 
-// ParseGomod 解析go.mod文件
-func ParseGomod(file *model.File) *model.DepGraph {
+// GoMod go.mod
+type GoMod struct {
+	Module    string
+	Go        string
+	Toolchain string
+	Require   []goRequire
+	Replace   goReplaces
+	File      *model.File
+}
 
-	root := &model.DepGraph{Path: file.Relpath()}
+// goRequire require指令
+type goRequire struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
 
-	var require bool
-	var replace bool
+// goReplace replace指令 OldVersion为空时替换所有版本 NewVersion为空时替换为本地目录
+type goReplace struct {
+	Old        string
+	OldVersion string
+	New        string
+	NewVersion string
+}
 
-	parseDepLine := func(line string) (name, version string) {
-		line = strings.TrimSpace(line)
-		words := strings.Fields(line)
-		if len(words) >= 2 {
-			name = strings.Trim(words[0], `'"`)
-			version = strings.TrimSuffix(words[1], "+incompatible")
+func (r goReplace) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", r.Old, r.OldVersion)) + " => " + strings.TrimSpace(fmt.Sprintf("%s %s", r.New, r.NewVersion))
+}
+
+type goReplaces []goReplace
+
+// find 查找模块对应的replace 指定版本的replace优先
+func (rs goReplaces) find(path, version string) (goReplace, bool) {
+	var r goReplace
+	found := false
+	for _, replace := range rs {
+		if replace.Old != path {
+			continue
+		}
+		if replace.OldVersion == version {
+			return replace, true
+		}
+		if replace.OldVersion == "" {
+			r, found = replace, true
 		}
-		return
 	}
+	return r, found
+}
 
-	deps := map[string]string{}
+// readGoDirectives 解析go.mod/go.work格式的指令
+// do.verb: 指令名 如require/replace
+// do.args: 指令参数
+// do.comment: 行尾注释 如indirect
+func readGoDirectives(reader io.Reader, do func(verb string, args []string, comment string)) {
+	var block string
+	model.ReadLine(reader, func(line string) {
 
-	file.ReadLineNoComment(&model.CommentType{
-		Simple: "//",
-	}, func(line string) {
+		var comment string
+		if i := strings.Index(line, "//"); i != -1 {
+			line, comment = line[:i], strings.TrimSpace(line[i+2:])
+		}
 
-		if strings.HasPrefix(line, "module") {
-			root.Name = strings.TrimSpace(strings.TrimPrefix(line, "module"))
+		words := strings.Fields(line)
+		for i := range words {
+			words[i] = strings.Trim(words[i], `'"`+"`")
+		}
+		if len(words) == 0 {
 			return
 		}
 
-		if strings.HasPrefix(line, "require") {
-			require = true
-			line = strings.TrimPrefix(line, "require")
-			if strings.TrimSpace(line) == "" {
-				return
+		if block != "" {
+			if words[0] == ")" {
+				block = ""
+			} else {
+				do(block, words, comment)
 			}
+			return
+		}
+
+		if len(words) == 2 && words[1] == "(" {
+			block = words[0]
+			return
 		}
+		do(words[0], words[1:], comment)
+	})
+}
+
+// ReadGoMod 读取go.mod
+func ReadGoMod(file *model.File) *GoMod {
+	var mod *GoMod
+	file.OpenReader(func(reader io.Reader) {
+		mod = readGoMod(reader)
+	})
+	if mod == nil {
+		mod = &GoMod{}
+	}
+	mod.File = file
+	return mod
+}
 
-		if strings.HasPrefix(line, "replace") {
-			replace = true
-			line = strings.TrimPrefix(line, "replace")
-			if strings.TrimSpace(line) == "" {
-				return
+func readGoMod(reader io.Reader) *GoMod {
+	mod := &GoMod{}
+	readGoDirectives(reader, func(verb string, args []string, comment string) {
+		switch verb {
+		case "module":
+			if len(args) > 0 {
+				mod.Module = args[0]
+			}
+		case "go":
+			if len(args) > 0 {
+				mod.Go = args[0]
+			}
+		case "toolchain":
+			if len(args) > 0 {
+				mod.Toolchain = args[0]
+			}
+		case "require":
+			if len(args) >= 2 {
+				mod.Require = append(mod.Require, goRequire{Path: args[0], Version: args[1], Indirect: comment == "indirect" || strings.HasPrefix(comment, "indirect;")})
+			}
+		case "replace":
+			if r, ok := parseGoReplace(args); ok {
+				mod.Replace = append(mod.Replace, r)
 			}
 		}
+	})
+	return mod
+}
 
-		if strings.HasPrefix(line, ")") {
-			require = false
-			replace = false
-			return
+// parseGoReplace 解析replace指令参数 old [v] => new [v]
+func parseGoReplace(args []string) (goReplace, bool) {
+	var r goReplace
+	i := -1
+	for j, arg := range args {
+		if arg == "=>" {
+			i = j
 		}
+	}
+	if i < 1 || i > 2 || len(args) < i+2 {
+		return r, false
+	}
+	r.Old = args[0]
+	if i == 2 {
+		r.OldVersion = args[1]
+	}
+	r.New = args[i+1]
+	if len(args) > i+2 {
+		r.NewVersion = args[i+2]
+	}
+	return r, true
+}
 
-		if require {
-			name, version := parseDepLine(line)
-			if name != "" {
-				deps[name] = version
-			}
+// complete go.mod是否包含所有间接依赖 go1.17开始go.mod中记录了构建所需的全部模块
+func (mod *GoMod) complete() bool {
+	var major, minor int
+	fmt.Sscanf(mod.Go, "%d.%d", &major, &minor)
+	return major > 1 || (major == 1 && minor >= 17)
+}
+
+// runtime 项目使用的go版本 优先使用toolchain指令
+func (mod *GoMod) runtime() string {
+	return goRuntimeVersion(mod.Go, mod.Toolchain)
+}
+
+// goRuntimeVersion go版本 toolchain: go1.21.5
+func goRuntimeVersion(goVersion, toolchain string) string {
+	if strings.HasPrefix(toolchain, "go") {
+		return strings.TrimPrefix(toolchain, "go")
+	}
+	return goVersion
+}
+
+// GoSum go.sum
+type GoSum struct {
+	// 模块的哈希 key:path@version
+	Hash map[string]string
+	// 模块版本 同一模块有多个版本时为最后记录的版本 key:path
+	Version map[string]string
+	// 模块路径 按记录顺序
+	Paths []string
+}
+
+// ReadGoSum 读取go.sum/go.work.sum
+func ReadGoSum(file *model.File) *GoSum {
+	sum := &GoSum{Hash: map[string]string{}, Version: map[string]string{}}
+	file.ReadLine(func(line string) {
+		words := strings.Fields(line)
+		if len(words) < 3 {
 			return
 		}
+		path := strings.Trim(words[0], `'"`)
+		version := strings.TrimSuffix(words[1], "/go.mod")
+		if version == words[1] {
+			sum.Hash[path+"@"+version] = words[2]
+		}
+		if _, ok := sum.Version[path]; !ok {
+			sum.Paths = append(sum.Paths, path)
+		}
+		sum.Version[path] = version
+	})
+	return sum
+}
 
-		if replace {
-			line = strings.TrimSpace(line)
-			i := strings.Index(line, "=>")
-			if i == -1 {
-				return
-			}
-			old := strings.TrimSpace(line[:i])
-			delete(deps, old)
-			name, version := parseDepLine(line[i+2:])
-			if name != "" {
-				deps[name] = version
-			}
-			return
+// goResolver 解析依赖模块实际使用的版本
+type goResolver struct {
+	// 按优先级排列的replace 如go.work中的replace优先于go.mod
+	replaces []goReplaces
+	// vendor/modules.txt中的模块 key:模块路径
+	vendor map[string]*model.DepGraph
+	// go.sum中记录的哈希
+	sums []*GoSum
+}
+
+// dep 依赖模块 应用replace及vendor中记录的版本
+func (r *goResolver) dep(path, version string, replaces goReplaces) *model.DepGraph {
+
+	if v, ok := r.vendor[path]; ok {
+		dep := *v
+		return &dep
+	}
+
+	for _, rs := range append(r.replaces, replaces) {
+		if replace, ok := rs.find(path, version); ok {
+			return r.integrity(newGoDep(path, version, &replace))
 		}
+	}
+	return r.integrity(newGoDep(path, version, nil))
+}
 
-	})
+// integrity 从go.sum中获取模块哈希
+func (r *goResolver) integrity(dep *model.DepGraph) *model.DepGraph {
+	if dep.Local {
+		return dep
+	}
+	for _, sum := range r.sums {
+		if sum == nil {
+			continue
+		}
+		if h, ok := sum.Hash[dep.Name+"@"+dep.Version]; ok {
+			dep.Integrity = h
+			break
+		}
+	}
+	return dep
+}
 
-	for name, version := range deps {
-		root.AppendChild(&model.DepGraph{
-			Name:    name,
-			Version: version,
-		})
+// newGoDep 依赖模块 r不为nil时使用replace替换后的模块
+func newGoDep(path, version string, r *goReplace) *model.DepGraph {
+
+	dep := &model.DepGraph{
+		Name:    path,
+		Version: strings.TrimSuffix(version, "+incompatible"),
 	}
 
+	if r != nil {
+		dep.Override = r.String()
+		if r.NewVersion == "" {
+			// 替换为本地目录
+			dep.Local = true
+		} else {
+			dep.Name = r.New
+			dep.Version = strings.TrimSuffix(r.NewVersion, "+incompatible")
+		}
+	}
+
+	return dep
+}
+
+// ParseGomod 解析go.mod文件 应用replace指令
+// sum: 同目录下的go.sum 不存在时为nil go1.17之前的go.mod中缺少的间接依赖从go.sum中获取
+func ParseGomod(mod *GoMod, sum *GoSum) *model.DepGraph {
+
+	root := &model.DepGraph{Name: mod.Module, Path: mod.File.Relpath()}
+	resolver := &goResolver{sums: []*GoSum{sum}}
+
+	required := map[string]bool{}
+	for _, req := range mod.Require {
+		required[req.Path] = true
+		root.AppendChild(resolver.dep(req.Path, req.Version, mod.Replace))
+	}
+
+	if sum != nil && !mod.complete() {
+		for _, path := range sum.Paths {
+			if !required[path] && path != mod.Module {
+				root.AppendChild(resolver.dep(path, sum.Version[path], mod.Replace))
+			}
+		}
+	}
+
+	root.AppendRuntime(model.Runtime_Go, mod.runtime())
+
 	return root
 }
 
 // ParseGosum 解析go.sum文件
 func ParseGosum(file *model.File) *model.DepGraph {
 
-	depMap := map[string]string{}
-	file.ReadLine(func(line string) {
-		line = strings.TrimSpace(line)
-		words := strings.Fields(line)
-		if len(words) >= 2 {
-			name := strings.Trim(words[0], `'"`)
-			version := strings.TrimSuffix(words[1], "/go.mod")
-			version = strings.TrimSuffix(version, "+incompatible")
-			depMap[name] = version
-		}
-	})
+	sum := ReadGoSum(file)
+	resolver := &goResolver{sums: []*GoSum{sum}}
 
 	root := &model.DepGraph{Path: file.Relpath()}
-	for name, version := range depMap {
-		root.AppendChild(&model.DepGraph{
-			Name:    name,
-			Version: version,
-		})
+	for _, path := range sum.Paths {
+		root.AppendChild(resolver.dep(path, sum.Version[path], nil))
 	}
 
 	sort.Slice(root.Children, func(i, j int) bool {
@@ -143,6 +341,12 @@ func GoModGraph(ctx context.Context, modfile *model.File) *model.DepGraph {
 		return nil
 	}
 
+	return ParseGoModGraph(modfile, output)
+}
+
+// ParseGoModGraph 解析 go mod graph 的输出
+func ParseGoModGraph(modfile *model.File, output []byte) *model.DepGraph {
+
 	_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph {
 		return &model.DepGraph{
 			Name:    s[0],
@@ -158,15 +362,38 @@ func GoModGraph(ctx context.Context, modfile *model.File) *model.DepGraph {
 		return _dep.LoadOrStore(s, "")
 	}
 
+	// 主模块及其声明的go版本
+	var main *model.DepGraph
+	var goVersion, toolchain string
+
 	scanner := bufio.NewScanner(bytes.NewReader(output))
 	for scanner.Scan() {
 		words := strings.Fields(strings.TrimSpace(scanner.Text()))
 		if len(words) == 2 {
+			// go@1.21 toolchain@go1.21.5 为go版本自身的依赖 不是组件
+			if strings.HasPrefix(words[0], "go@") || strings.HasPrefix(words[0], "toolchain@") {
+				continue
+			}
 			parent := parse(words[0])
+			if !strings.Contains(words[0], "@") {
+				main = parent
+			}
+			// go1.21开始会输出 module go@1.21 toolchain@go1.21.5 仅记录主模块的版本
+			if name, version, ok := strings.Cut(words[1], "@"); ok && (name == "go" || name == "toolchain") {
+				switch {
+				case parent != main:
+				case name == "go":
+					goVersion = version
+				default:
+					toolchain = version
+				}
+				continue
+			}
 			child := parse(words[1])
 			parent.AppendChild(child)
 		}
 	}
+	main.AppendRuntime(model.Runtime_Go, goRuntimeVersion(goVersion, toolchain))
 
 	root := &model.DepGraph{Path: modfile.Relpath()}
 	_dep.Range(func(k string, v *model.DepGraph) bool {
@@ -0,0 +1,195 @@
+package golang
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/common"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/cache"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var defaultGoproxyRepo = []common.RepoConfig{
+	{Url: "https://proxy.golang.org"},
+}
+
+// RegisterGoproxyRepo 注册goproxy仓库 支持http(s)及file://
+// https://go.dev/ref/mod#goproxy-protocol
+func RegisterGoproxyRepo(repos ...common.RepoConfig) {
+	newRepo := common.TrimRepo(repos...)
+	if len(newRepo) > 0 {
+		defaultGoproxyRepo = newRepo
+	}
+}
+
+// goModOrigin 获取模块指定版本的go.mod 获取失败时返回nil
+var goModOrigin = func(path, version string) *GoMod {
+
+	var mod *GoMod
+
+	// 读取缓存
+	cachePath := cache.Path("", goEscapePath(path), goEscapePath(version), model.Lan_Golang)
+	cache.Load(cachePath, func(reader io.Reader) {
+		mod = readGoMod(reader)
+	})
+	if mod != nil {
+		return mod
+	}
+
+	// 从goproxy下载
+	route := fmt.Sprintf("%s/@v/%s.mod", goEscapePath(path), goEscapePath(version))
+	common.DownloadUrlFromRepos(route, func(repo common.RepoConfig, r io.Reader) {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			logs.Warn(err)
+			return
+		}
+		mod = readGoMod(bytes.NewReader(data))
+		cache.Save(cachePath, bytes.NewReader(data))
+	}, defaultGoproxyRepo...)
+
+	return mod
+}
+
+// RegisterGoModOrigin 注册go.mod数据源
+func RegisterGoModOrigin(origin func(path, version string) *GoMod) {
+	if origin != nil {
+		goModOrigin = origin
+	}
+}
+
+// goEscapePath 模块路径及版本转义 大写字母转为!加小写字母
+// https://go.dev/ref/mod#goproxy-protocol
+func goEscapePath(path string) string {
+	var sb strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('!')
+			sb.WriteRune(unicode.ToLower(r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// goVersionLess 比较模块版本 v1<v2
+func goVersionLess(v1, v2 string) bool {
+	s1, err1 := semver.NewVersion(v1)
+	s2, err2 := semver.NewVersion(v2)
+	if err1 != nil || err2 != nil {
+		return v1 < v2
+	}
+	return s1.LessThan(s2)
+}
+
+// ResolveGomod 通过goproxy获取依赖模块的go.mod 使用最小版本选择(MVS)计算构建列表
+// https://go.dev/ref/mod#minimal-version-selection
+// sum: 同目录下的go.sum 不存在时为nil
+// 无法获取任何依赖模块的go.mod或任务取消时返回nil
+func ResolveGomod(ctx context.Context, mod *GoMod, sum *GoSum) *model.DepGraph {
+
+	// 模块各版本的依赖 key:path@version
+	reqs := map[string][]goRequire{}
+	// 构建列表中选择的版本 key:path
+	selected := map[string]string{}
+
+	fetched := false
+	queue := append([]goRequire{}, mod.Require...)
+	for len(queue) > 0 {
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		req := queue[0]
+		queue = queue[1:]
+
+		key := req.Path + "@" + req.Version
+		if _, ok := reqs[key]; ok || req.Path == mod.Module {
+			continue
+		}
+
+		if v, ok := selected[req.Path]; !ok || goVersionLess(v, req.Version) {
+			selected[req.Path] = req.Version
+		}
+
+		var m *GoMod
+		if r, ok := mod.Replace.find(req.Path, req.Version); !ok {
+			m = goModOrigin(req.Path, req.Version)
+			fetched = fetched || m != nil
+		} else if r.NewVersion != "" {
+			m = goModOrigin(r.New, r.NewVersion)
+			fetched = fetched || m != nil
+		} else if mod.File != nil {
+			// 替换为本地目录
+			m = ReadGoMod(model.NewFile(filepath.Join(filepath.Dir(mod.File.Abspath()), filepath.FromSlash(r.New), "go.mod"), ""))
+		}
+
+		reqs[key] = nil
+		if m != nil {
+			reqs[key] = m.Require
+			queue = append(queue, m.Require...)
+		}
+	}
+
+	if !fetched {
+		return nil
+	}
+
+	root := &model.DepGraph{Name: mod.Module, Path: mod.File.Relpath()}
+	resolver := &goResolver{sums: []*GoSum{sum}}
+
+	_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph {
+		return resolver.dep(s[0], selected[s[0]], mod.Replace)
+	}).LoadOrStore
+
+	paths := make([]string, 0, len(selected))
+	for path := range selected {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		dep := _dep(path)
+		for _, req := range reqs[path+"@"+selected[path]] {
+			if _, ok := selected[req.Path]; ok {
+				dep.AppendChild(_dep(req.Path))
+			}
+		}
+	}
+
+	// 直接依赖 及未被其他模块依赖的间接依赖
+	for _, req := range mod.Require {
+		if !req.Indirect {
+			root.AppendChild(_dep(req.Path))
+		}
+	}
+	reached := map[*model.DepGraph]bool{}
+	reach := func(p, n *model.DepGraph) bool {
+		reached[n] = true
+		return true
+	}
+	root.ForEachNode(reach)
+	for _, path := range paths {
+		if dep := _dep(path); !reached[dep] {
+			root.AppendChild(dep)
+			dep.ForEachNode(reach)
+		}
+	}
+
+	root.AppendRuntime(model.Runtime_Go, mod.runtime())
+
+	return root
+}
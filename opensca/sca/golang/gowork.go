@@ -0,0 +1,87 @@
+package golang
+
+import (
+	"io"
+	"path"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// GoWork go.work
+type GoWork struct {
+	Go        string
+	Toolchain string
+	// workspace中的模块目录 相对于go.work所在目录
+	Use     []string
+	Replace goReplaces
+	File    *model.File
+}
+
+// ReadGoWork 读取go.work
+func ReadGoWork(file *model.File) *GoWork {
+	work := &GoWork{File: file}
+	file.OpenReader(func(reader io.Reader) {
+		readGoDirectives(reader, func(verb string, args []string, comment string) {
+			switch verb {
+			case "go":
+				if len(args) > 0 {
+					work.Go = args[0]
+				}
+			case "toolchain":
+				if len(args) > 0 {
+					work.Toolchain = args[0]
+				}
+			case "use":
+				if len(args) > 0 {
+					work.Use = append(work.Use, path.Clean(strings.ReplaceAll(args[0], `\`, `/`)))
+				}
+			case "replace":
+				if r, ok := parseGoReplace(args); ok {
+					work.Replace = append(work.Replace, r)
+				}
+			}
+		})
+	})
+	return work
+}
+
+// ParseGoWork 解析go.work workspace中的模块作为项目模块 模块间的依赖使用workspace中的模块
+// mods: workspace中的模块 key:go.mod相对于go.work所在目录的路径
+// sums: workspace中的go.sum及go.work.sum
+// vendor: go.work所在目录的vendor/modules.txt 不存在时为nil
+func ParseGoWork(work *GoWork, mods map[string]*GoMod, sums []*GoSum, vendor map[string]*model.DepGraph) *model.DepGraph {
+
+	root := &model.DepGraph{Path: work.File.Relpath()}
+	resolver := &goResolver{replaces: []goReplaces{work.Replace}, vendor: vendor, sums: sums}
+
+	// key:模块路径
+	members := map[string]*model.DepGraph{}
+	var used []*GoMod
+	for _, dir := range work.Use {
+		mod, ok := mods[dir]
+		if !ok {
+			continue
+		}
+		used = append(used, mod)
+		member := &model.DepGraph{Name: mod.Module, Path: mod.File.Relpath(), Local: true}
+		members[mod.Module] = member
+		root.AppendChild(member)
+	}
+
+	for _, mod := range used {
+		member := members[mod.Module]
+		for _, req := range mod.Require {
+			if dep, ok := members[req.Path]; ok {
+				member.AppendChild(dep)
+			} else {
+				member.AppendChild(resolver.dep(req.Path, req.Version, mod.Replace))
+			}
+		}
+	}
+
+	// workspace使用go.work中声明的go版本
+	root.AppendRuntime(model.Runtime_Go, goRuntimeVersion(work.Go, work.Toolchain))
+
+	return root
+}
@@ -2,8 +2,10 @@ package golang
 
 import (
 	"context"
-	"path/filepath"
+	"path"
+	"strings"
 
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
 )
@@ -15,55 +17,117 @@ func (sca Sca) Language() model.Language {
 }
 
 func (sca Sca) Filter(relpath string) bool {
-	return filter.GoMod(relpath) || filter.GoSum(relpath) || filter.GoPkgToml(relpath) || filter.GoPkgLock(relpath)
+	return filter.GoMod(relpath) ||
+		filter.GoSum(relpath) ||
+		filter.GoWork(relpath) ||
+		filter.GoVendor(relpath) ||
+		filter.GoPkgToml(relpath) ||
+		filter.GoPkgLock(relpath) ||
+		filter.Executable(relpath)
 }
 
 func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
 
-	// map[dir]*File
-	gomod := map[string]*model.File{}
+	path2dir := func(relpath string) string { return path.Dir(strings.ReplaceAll(relpath, `\`, `/`)) }
+
+	// map[dir]
+	gomod := map[string]*GoMod{}
 	gosum := map[string]*model.File{}
+	gowork := map[string]*GoWork{}
+	worksum := map[string]*model.File{}
+	vendor := map[string]*model.File{}
 	pkglock := map[string]*model.File{}
 	pkgtoml := map[string]*model.File{}
 
 	// 记录相关文件
 	for _, f := range files {
-		dir := filepath.Dir(f.Relpath())
-		if filter.GoPkgToml(f.Relpath()) {
+		dir := path2dir(f.Relpath())
+		switch {
+		case filter.GoPkgToml(f.Relpath()):
 			pkgtoml[dir] = f
-		}
-		if filter.GoPkgLock(f.Relpath()) {
+		case filter.GoPkgLock(f.Relpath()):
 			pkglock[dir] = f
+		case filter.GoMod(f.Relpath()):
+			gomod[dir] = ReadGoMod(f)
+		case filter.GoWork(f.Relpath()):
+			gowork[dir] = ReadGoWork(f)
+		case filter.GoSum(f.Relpath()):
+			if strings.HasSuffix(f.Relpath(), "go.work.sum") {
+				worksum[dir] = f
+			} else {
+				gosum[dir] = f
+			}
+		case filter.GoVendor(f.Relpath()):
+			// key:vendor所在的模块目录
+			vendor[path.Dir(dir)] = f
+		case filter.Executable(f.Relpath()):
+			// 解析go可执行文件
+			if bin := ParseGoBinary(f); bin != nil {
+				call(f, bin)
+			}
 		}
-		if filter.GoMod(f.Relpath()) {
-			gomod[dir] = f
+	}
+
+	readSum := func(f *model.File) *GoSum {
+		if f == nil {
+			return nil
+		}
+		return ReadGoSum(f)
+	}
+
+	// workspace中的模块作为项目模块
+	for dir, work := range gowork {
+		mods := map[string]*GoMod{}
+		sums := []*GoSum{readSum(worksum[dir])}
+		for _, use := range work.Use {
+			moddir := path.Join(dir, use)
+			if mod, ok := gomod[moddir]; ok {
+				mods[use] = mod
+				sums = append(sums, readSum(gosum[moddir]))
+				delete(gomod, moddir)
+				delete(gosum, moddir)
+			}
 		}
-		if filter.GoSum(f.Relpath()) {
-			gosum[dir] = f
+		var vendored map[string]*model.DepGraph
+		if f, ok := vendor[dir]; ok {
+			vendored = ReadGoVendor(f)
+			delete(vendor, dir)
 		}
+		call(work.File, ParseGoWork(work, mods, sums, vendored))
+	}
+
+	// 优先使用vendor/modules.txt
+	for dir, f := range vendor {
+		call(f, ParseGoVendor(f, gomod[dir]))
+		delete(gomod, dir)
+		delete(gosum, dir)
 	}
 
 	// 尝试调用 go mod graph
-	for dir, f := range gomod {
-		graph := GoModGraph(ctx, f)
+	for dir, mod := range gomod {
+		graph := GoModGraph(ctx, mod.File)
 		if graph != nil && len(graph.Children) > 0 {
-			call(f, graph)
+			call(mod.File, graph)
 			delete(gomod, dir)
 			delete(gosum, dir)
 		}
 	}
 
-	// 静态解析go.sum
-	for dir, f := range gosum {
-		sum := ParseGosum(f)
-		call(f, sum)
-		delete(gomod, dir)
+	// 通过goproxy计算构建列表 获取失败时静态解析go.mod 缺少的间接依赖从go.sum中获取
+	for dir, mod := range gomod {
+		sum := readSum(gosum[dir])
+		if graph := ResolveGomod(ctx, mod, sum); graph != nil {
+			call(mod.File, graph)
+		} else {
+			logs.Warnf("resolve %s with goproxy fail, parse go.mod statically", mod.File.Relpath())
+			call(mod.File, ParseGomod(mod, sum))
+		}
+		delete(gosum, dir)
 	}
 
-	// 静态解析go.mod
-	for _, f := range gomod {
-		mod := ParseGomod(f)
-		call(f, mod)
+	// 静态解析go.sum
+	for _, f := range gosum {
+		call(f, ParseGosum(f))
 	}
 
 	// 静态解析gopkg.lock
@@ -0,0 +1,58 @@
+package golang
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// ReadGoVendor 读取vendor/modules.txt中实际使用的模块 key:模块路径
+// # example.com/old v1.0.0 => example.com/new v1.1.0
+// ## explicit; go 1.20
+// example.com/old/pkg
+func ReadGoVendor(file *model.File) map[string]*model.DepGraph {
+	mods := map[string]*model.DepGraph{}
+	file.ReadLine(func(line string) {
+		if !strings.HasPrefix(line, "# ") {
+			return
+		}
+		words := strings.Fields(strings.TrimPrefix(line, "# "))
+		// 未指定版本的为replace声明
+		if len(words) < 2 || words[1] == "=>" {
+			return
+		}
+		var r *goReplace
+		if len(words) > 3 && words[2] == "=>" {
+			replace := goReplace{Old: words[0], OldVersion: words[1], New: words[3]}
+			if len(words) > 4 {
+				replace.NewVersion = words[4]
+			}
+			r = &replace
+		}
+		mods[words[0]] = newGoDep(words[0], words[1], r)
+	})
+	return mods
+}
+
+// ParseGoVendor 解析vendor/modules.txt
+// mod: 对应的go.mod 不存在时为nil
+func ParseGoVendor(file *model.File, mod *GoMod) *model.DepGraph {
+	root := &model.DepGraph{Path: file.Relpath()}
+	if mod != nil {
+		root.Name = mod.Module
+	}
+	mods := ReadGoVendor(file)
+	paths := make([]string, 0, len(mods))
+	for path := range mods {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		root.AppendChild(mods[path])
+	}
+	if mod != nil {
+		root.AppendRuntime(model.Runtime_Go, mod.runtime())
+	}
+	return root
+}
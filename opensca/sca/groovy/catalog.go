@@ -0,0 +1,182 @@
+package groovy
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// VersionCatalog gradle版本目录(libs.versions.toml)
+// https://docs.gradle.org/current/userguide/platforms.html
+type VersionCatalog struct {
+	// 目录访问名 libs.versions.toml=>libs
+	Name string
+	// 组件别名 key为规范化后的别名
+	Libraries map[string]*CatalogLibrary
+	// 组件集合 key为规范化后的别名
+	Bundles map[string][]string
+	// 插件 key为规范化后的别名
+	Plugins map[string]*CatalogLibrary
+}
+
+// CatalogLibrary 版本目录中定义的组件
+type CatalogLibrary struct {
+	Group   string
+	Name    string
+	Version string
+}
+
+// catalogAlias 规范化别名 gradle中别名的-_.分隔符在访问时均为.
+func catalogAlias(alias string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+}
+
+// ParseVersionCatalog 解析gradle版本目录文件
+func ParseVersionCatalog(file *model.File) *VersionCatalog {
+
+	catalog := &VersionCatalog{
+		Name:      strings.TrimSuffix(filepath.Base(file.Relpath()), ".versions.toml"),
+		Libraries: map[string]*CatalogLibrary{},
+		Bundles:   map[string][]string{},
+		Plugins:   map[string]*CatalogLibrary{},
+	}
+
+	data := struct {
+		Versions  map[string]any      `toml:"versions"`
+		Libraries map[string]any      `toml:"libraries"`
+		Bundles   map[string][]string `toml:"bundles"`
+		Plugins   map[string]any      `toml:"plugins"`
+	}{}
+
+	file.OpenReader(func(reader io.Reader) {
+		if _, err := toml.NewDecoder(reader).Decode(&data); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	// 解析版本号
+	// version = "1.0" | version.ref = "x" | version = { strictly = "[1.0, 2.0[", prefer = "1.5" }
+	var version func(v any) string
+	version = func(v any) string {
+		switch ver := v.(type) {
+		case string:
+			return ver
+		case map[string]any:
+			if ref, ok := ver["ref"].(string); ok {
+				return version(data.Versions[ref])
+			}
+			for _, k := range []string{"prefer", "require", "strictly"} {
+				if s, ok := ver[k].(string); ok && s != "" {
+					return trimVersionRange(s)
+				}
+			}
+		}
+		return ""
+	}
+
+	// 解析组件定义
+	// alias = "group:name:version" | { module = "group:name", version = ... } | { group = "group", name = "name", version = ... }
+	library := func(v any) *CatalogLibrary {
+		lib := &CatalogLibrary{}
+		switch def := v.(type) {
+		case string:
+			gav := strings.Split(def, ":")
+			if len(gav) < 2 {
+				return nil
+			}
+			lib.Group, lib.Name = gav[0], gav[1]
+			if len(gav) > 2 {
+				lib.Version = gav[2]
+			}
+		case map[string]any:
+			if module, ok := def["module"].(string); ok {
+				ga := strings.Split(module, ":")
+				if len(ga) != 2 {
+					return nil
+				}
+				lib.Group, lib.Name = ga[0], ga[1]
+			} else {
+				lib.Group, _ = def["group"].(string)
+				lib.Name, _ = def["name"].(string)
+			}
+			lib.Version = version(def["version"])
+		default:
+			return nil
+		}
+		return lib
+	}
+
+	for alias, def := range data.Libraries {
+		if lib := library(def); lib != nil {
+			catalog.Libraries[catalogAlias(alias)] = lib
+		}
+	}
+
+	for alias, libs := range data.Bundles {
+		for _, lib := range libs {
+			catalog.Bundles[catalogAlias(alias)] = append(catalog.Bundles[catalogAlias(alias)], catalogAlias(lib))
+		}
+	}
+
+	// 插件 alias = "id:version" | { id = "id", version = ... }
+	for alias, def := range data.Plugins {
+		plugin := &CatalogLibrary{}
+		switch p := def.(type) {
+		case string:
+			if i := strings.LastIndex(p, ":"); i != -1 {
+				plugin.Group, plugin.Version = p[:i], p[i+1:]
+			} else {
+				plugin.Group = p
+			}
+		case map[string]any:
+			plugin.Group, _ = p["id"].(string)
+			plugin.Version = version(p["version"])
+		}
+		if plugin.Group != "" {
+			catalog.Plugins[catalogAlias(alias)] = plugin
+		}
+	}
+
+	return catalog
+}
+
+// Lookup 通过访问路径查找组件 如 libs.groovy.core/libs.bundles.groovy
+// accessor: 去除目录名后的访问路径 如 groovy.core/bundles.groovy
+func (catalog *VersionCatalog) Lookup(accessor string) []*CatalogLibrary {
+
+	if catalog == nil {
+		return nil
+	}
+
+	accessor = strings.TrimSuffix(accessor, ".get")
+	accessor = strings.TrimSuffix(accessor, ".asProvider")
+
+	if strings.HasPrefix(accessor, "bundles.") {
+		var libs []*CatalogLibrary
+		for _, alias := range catalog.Bundles[strings.TrimPrefix(accessor, "bundles.")] {
+			if lib, ok := catalog.Libraries[alias]; ok {
+				libs = append(libs, lib)
+			}
+		}
+		return libs
+	}
+
+	if lib, ok := catalog.Libraries[accessor]; ok {
+		return []*CatalogLibrary{lib}
+	}
+
+	return nil
+}
+
+// trimVersionRange 从版本范围中取一个确定版本 [1.0, 2.0[ => 1.0
+func trimVersionRange(version string) string {
+	if !strings.ContainsAny(version, "()[]") {
+		return version
+	}
+	lr := strings.Split(version, ",")
+	return strings.TrimSpace(strings.Trim(lr[0], "()[]"))
+}
@@ -17,80 +17,422 @@ import (
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/java"
 )
 
+// gradleDecl gradle脚本中声明的依赖
+type gradleDecl struct {
+	Vendor  string
+	Name    string
+	Version string
+	// 声明依赖使用的configuration
+	Conf string
+	// platform/enforcedPlatform 引入的bom
+	Platform bool
+}
+
+// gradleBuild gradle构建脚本解析结果
+type gradleBuild struct {
+	File *model.File
+	// 声明的依赖
+	Deps []*gradleDecl
+	// 依赖的其他项目 project(':lib') | projects.lib
+	Projects []string
+}
+
+var (
+	// implementation 'g:a:v' | implementation("g:a") | api(platform("g:a:v"))
+	declReg = regexp.MustCompile(`^\s*(\w+)(?:\s+|\s*\(\s*)(?:(platform|enforcedPlatform)\s*\(\s*)?['"]([\w.\-]+):([\w.\-]+)(?::([^\s:'"@]*))?[^'"]*['"]`)
+	// implementation libs.groovy.core | implementation(libs.bundles.groovy)
+	catalogReg = regexp.MustCompile(`^\s*(\w+)(?:\s+|\s*\(\s*)(?:(platform|enforcedPlatform)\s*\(\s*)?(\w+)\.([\w.]+)`)
+	// implementation project(':lib') | implementation(project(path = ":lib"))
+	projectReg = regexp.MustCompile(`\bproject\s*\(\s*(?:path\s*[:=]\s*)?['"](:?[^'"]*)['"]`)
+	// implementation(projects.lib)
+	projectsReg = regexp.MustCompile(`\bprojects\.([\w.]+)`)
+	// 行首的configuration
+	confReg = regexp.MustCompile(`^\s*(\w+)`)
+)
+
+var (
+	// id 'org.springframework.boot' version '3.1.0' | id("org.springframework.boot") version "3.1.0"
+	pluginReg = regexp.MustCompile(`^\s*id\s*\(?\s*['"]([\w.\-]+)['"]\s*\)?(?:\s*version\s*\(?\s*['"]([^'"]+)['"])?`)
+	// kotlin("jvm") version "1.9.0"
+	kotlinPluginReg = regexp.MustCompile(`^\s*kotlin\s*\(\s*['"]([\w.\-]+)['"]\s*\)(?:\s*version\s*['"]([^'"]+)['"])?`)
+	// alias(libs.plugins.spring.boot)
+	aliasPluginReg = regexp.MustCompile(`^\s*alias\s*\(\s*(\w+)\.plugins\.([\w.]+)\s*\)`)
+)
+
+const (
+	// plugins{}中声明的插件使用的configuration标记
+	gradlePluginConf = "plugin"
+	// gradle插件仓库
+	gradlePluginPortal = "https://plugins.gradle.org/m2"
+)
+
+// pluginMarker 插件id对应的插件标记组件
+// https://docs.gradle.org/current/userguide/plugins.html#sec:plugin_markers
+func pluginMarker(id, version string) *gradleDecl {
+	return &gradleDecl{
+		Vendor:  id,
+		Name:    id + ".gradle.plugin",
+		Version: version,
+		Conf:    gradlePluginConf,
+	}
+}
+
+// parseGradleBuild 解析gradle构建脚本
+// v: 变量表
+// catalogs: 可用的版本目录 key:目录访问名
+// plugins: settings中pluginManagement声明的插件版本 key:插件id
+func parseGradleBuild(file *model.File, v Variable, catalogs map[string]*VersionCatalog, plugins map[string]string) *gradleBuild {
+
+	build := &gradleBuild{File: file}
+
+	file.ReadLineNoComment(model.CTypeComment, func(line string) {
+
+		line = v.Replace(line)
+
+		// 插件声明
+		if m := aliasPluginReg.FindStringSubmatch(line); len(m) == 3 {
+			if catalog, ok := catalogs[m[1]]; ok {
+				if plugin, ok := catalog.Plugins[catalogAlias(m[2])]; ok && plugin.Version != "" {
+					build.Deps = append(build.Deps, pluginMarker(plugin.Group, plugin.Version))
+				}
+			}
+			return
+		}
+		for _, re := range []*regexp.Regexp{pluginReg, kotlinPluginReg} {
+			m := re.FindStringSubmatch(line)
+			if len(m) != 3 {
+				continue
+			}
+			id, version := m[1], m[2]
+			if re == kotlinPluginReg {
+				id = "org.jetbrains.kotlin." + id
+			}
+			if version == "" {
+				version = plugins[id]
+			}
+			// 未指定版本的插件为gradle内置插件
+			if version != "" && !strings.Contains(version, "$") {
+				build.Deps = append(build.Deps, pluginMarker(id, version))
+			}
+			return
+		}
+
+		for _, m := range projectReg.FindAllStringSubmatch(line, -1) {
+			build.Projects = append(build.Projects, gradleProjectPath(m[1]))
+		}
+		for _, m := range projectsReg.FindAllStringSubmatch(line, -1) {
+			build.Projects = append(build.Projects, m[1])
+		}
+
+		// 标准依赖声明
+		if m := declReg.FindStringSubmatch(line); len(m) == 6 {
+			if !strings.Contains(m[5], "$") {
+				build.Deps = append(build.Deps, &gradleDecl{
+					Vendor:   m[3],
+					Name:     m[4],
+					Version:  m[5],
+					Conf:     m[1],
+					Platform: m[2] != "",
+				})
+			}
+			return
+		}
+
+		// 版本目录引用
+		if m := catalogReg.FindStringSubmatch(line); len(m) == 5 {
+			if catalog, ok := catalogs[m[3]]; ok {
+				for _, lib := range catalog.Lookup(m[4]) {
+					build.Deps = append(build.Deps, &gradleDecl{
+						Vendor:   lib.Group,
+						Name:     lib.Name,
+						Version:  lib.Version,
+						Conf:     m[1],
+						Platform: m[2] != "",
+					})
+				}
+				return
+			}
+		}
+
+		var conf string
+		if m := confReg.FindStringSubmatch(line); len(m) == 2 {
+			conf = m[1]
+		}
+
+		for _, re := range regexs {
+			match := re.FindStringSubmatch(line)
+			if len(match) < 4 {
+				continue
+			}
+
+			vendor := match[1]
+			name := match[2]
+			version := match[3]
+			index := strings.Index(version, "@")
+			if index != -1 {
+				version = version[:index]
+			}
+
+			if version == "" || strings.Contains(version, "$") {
+				continue
+			}
+
+			build.Deps = append(build.Deps, &gradleDecl{
+				Vendor:  vendor,
+				Name:    name,
+				Version: version,
+				Conf:    conf,
+			})
+		}
+	})
+
+	return build
+}
+
+// gradleScope 将configuration映射为组件作用域
+// https://docs.gradle.org/current/userguide/java_plugin.html#sec:java_plugin_and_dependency_management
+func gradleScope(conf string) model.Scope {
+	c := strings.ToLower(conf)
+	switch {
+	// 构建插件及构建脚本classpath
+	case c == gradlePluginConf || strings.HasPrefix(c, "buildscript.") || c == "classpath":
+		return model.Scope_Plugin
+	// 注解处理器
+	case strings.Contains(c, "annotationprocessor") || strings.HasPrefix(c, "kapt") || strings.HasPrefix(c, "ksp"):
+		return model.Scope_Build
+	// test/androidTest/integrationTest等测试configuration
+	case strings.Contains(c, "test"):
+		return model.Scope_Test
+	case strings.Contains(c, "runtime"):
+		return model.Scope_Runtime
+	case strings.Contains(c, "compile") || strings.Contains(c, "implementation") || strings.HasSuffix(c, "api") || c == "default":
+		return model.Scope_Compile
+	}
+	return model.Scope_None
+}
+
+// gradleScopes 合并多个configuration的作用域
+func gradleScopes(confs []string) model.Scope {
+	scope := model.Scope_None
+	for _, conf := range confs {
+		scope = scope.Merge(gradleScope(conf))
+	}
+	return scope
+}
+
+// inheritScope 未设置作用域的间接依赖继承父组件作用域
+func inheritScope(root *model.DepGraph) {
+	root.ForEachNode(func(p, n *model.DepGraph) bool {
+		if p != nil && n.Scope == model.Scope_None {
+			n.Scope = p.Scope
+		}
+		return true
+	})
+}
+
 // ParseGradle 解析gradle脚本
 func ParseGradle(ctx context.Context, files []*model.File) []*model.DepGraph {
 
 	v := Variable{}
 	gradle := []*model.File{}
+	// map[dir]
+	settings := map[string]*GradleSettings{}
+	// map[dir]
+	locks := map[string][]*GradleLock{}
+	buildLocks := map[string][]*GradleLock{}
+	// map[dir][name]
+	catalogs := map[string]map[string]*VersionCatalog{}
+
 	for _, f := range files {
-		if filter.GroovyGradle(f.Relpath()) {
+		rel := f.Relpath()
+		if filter.GroovyGradleSettings(rel) {
+			v.Scan(f)
+			settings[filepath.Dir(rel)] = ParseGradleSettings(f)
+		} else if filter.GroovyGradle(rel) {
 			v.Scan(f)
 			gradle = append(gradle, f)
+		} else if filter.GroovyGradleLock(rel) {
+			dir := gradleLockDir(rel)
+			// buildscript的锁文件记录的是构建脚本classpath
+			if strings.HasPrefix(filepath.Base(rel), "buildscript-") {
+				buildLocks[dir] = append(buildLocks[dir], ParseGradleLockfile(f)...)
+			} else {
+				locks[dir] = append(locks[dir], ParseGradleLockfile(f)...)
+			}
+		} else if filter.GroovyVersionCatalog(rel) {
+			// 版本目录默认位于根项目的gradle目录下
+			dir := filepath.Dir(rel)
+			if filepath.Base(dir) == "gradle" {
+				dir = filepath.Dir(dir)
+			}
+			catalog := ParseVersionCatalog(f)
+			if catalogs[dir] == nil {
+				catalogs[dir] = map[string]*VersionCatalog{}
+			}
+			catalogs[dir][catalog.Name] = catalog
 		}
 	}
 
-	var roots []*model.DepGraph
+	// 记录每个构建脚本的依赖图
+	builds := []*gradleBuild{}
+	buildRoots := map[*gradleBuild]*model.DepGraph{}
 
 	for _, f := range gradle {
 
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		dir := filepath.Dir(f.Relpath())
+		catalog, _ := findUp(catalogs, dir)
+		var plugins map[string]string
+		if s, ok := findUp(settings, dir); ok {
+			plugins = s.Plugins
+		}
+		build := parseGradleBuild(f, v, catalog, plugins)
+		builds = append(builds, build)
+
+		root := &model.DepGraph{Path: f.Relpath()}
+		buildRoots[build] = root
+
+		// 存在锁文件时以锁文件为准
+		lock, locked := locks[dir]
+		buildLock, buildLocked := buildLocks[dir]
+
+		// 记录直接依赖的作用域 map[groupId:artifactId]
+		scopes := map[string]model.Scope{}
+
+		// 借助java模块解析间接依赖
+		virPom := &java.Pom{File: model.NewFile(root.Path, root.Path)}
+		for _, d := range build.Deps {
+			scope := gradleScope(d.Conf)
+			if scope == model.Scope_Plugin && buildLocked || scope != model.Scope_Plugin && locked {
+				continue
+			}
+			dep := &java.PomDependency{GroupId: d.Vendor, ArtifactId: d.Name, Version: d.Version}
+			// platform引入的bom作为import的dependencyManagement
+			if d.Platform {
+				dep.Type = "pom"
+				dep.Scope = "import"
+				virPom.DependencyManagement = append(virPom.DependencyManagement, dep)
+				continue
+			}
+			if scope.Develop() {
+				dep.Scope = "test"
+			}
+			// 插件标记组件发布在gradle插件仓库
+			if scope == model.Scope_Plugin && len(virPom.Repositories) == 0 {
+				virPom.Repositories = append(virPom.Repositories, gradlePluginPortal)
+			}
+			key := d.Vendor + ":" + d.Name
+			scopes[key] = scopes[key].Merge(scope)
+			virPom.Dependencies = append(virPom.Dependencies, dep)
+		}
+		java.ParsePoms(ctx, []*java.Pom{virPom}, nil, func(pom *java.Pom, pomResult *model.DepGraph) {
+			for _, c := range pomResult.Children {
+				c.Scope = scopes[c.Vendor+":"+c.Name]
+			}
+			inheritScope(pomResult)
+			root = pomResult
+			buildRoots[build] = pomResult
+		})
+
+		// 添加锁文件中的组件
 		_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph {
 			return &model.DepGraph{
 				Vendor:  s[0],
 				Name:    s[1],
 				Version: s[2],
-				Develop: s[3] == "dev",
 			}
 		}).LoadOrStore
+		for _, l := range append(lock, buildLock...) {
+			dep := _dep(l.GroupId, l.ArtifactId, l.Version)
+			dep.Scope = dep.Scope.Merge(gradleScopes(l.Configurations))
+			dep.Develop = dep.Scope.Develop()
+			root.AppendChild(dep)
+		}
+	}
 
-		root := &model.DepGraph{Path: f.Relpath()}
-
-		f.ReadLineNoComment(model.CTypeComment, func(line string) {
-
-			line = v.Replace(line)
-
-			for _, re := range regexs {
-				match := re.FindStringSubmatch(line)
-				if len(match) < 4 {
-					continue
-				}
-
-				vendor := match[1]
-				name := match[2]
-				version := match[3]
-				index := strings.Index(version, "@")
-				if index != -1 {
-					version = version[:index]
-				}
+	var roots []*model.DepGraph
 
-				if version == "" || strings.Contains(version, "$") {
-					continue
-				}
+	// 按settings.gradle组织多项目构建
+	settingsRoots := map[*GradleSettings]*model.DepGraph{}
+	projectRoots := map[*GradleSettings]map[string]*model.DepGraph{}
+	buildSettings := map[*gradleBuild]*GradleSettings{}
+	for _, build := range builds {
+		root := buildRoots[build]
+		dir := filepath.Dir(build.File.Relpath())
+		s, ok := findUp(settings, dir)
+		if !ok {
+			roots = append(roots, root)
+			continue
+		}
+		path, ok := s.Project(dir)
+		if !ok {
+			roots = append(roots, root)
+			continue
+		}
+		if _, ok := settingsRoots[s]; !ok {
+			settingsRoots[s] = &model.DepGraph{Path: s.File.Relpath()}
+			projectRoots[s] = map[string]*model.DepGraph{}
+			roots = append(roots, settingsRoots[s])
+		}
+		if path == ":" {
+			root.Name = s.Name
+		} else {
+			root.Name = path[strings.LastIndex(path, ":")+1:]
+		}
+		buildSettings[build] = s
+		projectRoots[s][path] = root
+		settingsRoots[s].AppendChild(root)
+	}
 
-				dev := ""
-				if strings.Contains(strings.ToLower(line), "testimplementation") {
-					dev = "dev"
+	// 记录项目间依赖
+	for build, s := range buildSettings {
+		for _, ref := range build.Projects {
+			var sub *model.DepGraph
+			if strings.HasPrefix(ref, ":") {
+				sub = projectRoots[s][ref]
+			} else {
+				// 类型安全的项目访问器 projects.libCore => :lib-core
+				for path, p := range projectRoots[s] {
+					if projectAccessor(path) == projectAccessor(ref) {
+						sub = p
+						break
+					}
 				}
-
-				root.AppendChild(_dep(vendor, name, version, dev))
 			}
+			if sub != nil && sub != buildRoots[build] {
+				buildRoots[build].AppendChild(sub)
+			}
+		}
+	}
 
-		})
+	return roots
+}
 
-		roots = append(roots, root)
-	}
+// projectAccessor 规范化项目访问路径 :lib-core => lib.libcore
+func projectAccessor(path string) string {
+	path = strings.ReplaceAll(strings.TrimPrefix(path, ":"), ":", ".")
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(path))
+}
 
-	// 借助java模块解析间接依赖
-	for i, root := range roots {
-		virPom := &java.Pom{File: model.NewFile(root.Path, root.Path)}
-		for _, dep := range root.Children {
-			virPom.Dependencies = append(virPom.Dependencies, &java.PomDependency{GroupId: dep.Vendor, ArtifactId: dep.Name, Version: dep.Version})
+// findUp 从dir开始逐级向上查找
+func findUp[T any](m map[string]T, dir string) (T, bool) {
+	for {
+		if v, ok := m[dir]; ok {
+			return v, true
 		}
-		java.ParsePoms(ctx, []*java.Pom{virPom}, nil, func(pom *java.Pom, pomResult *model.DepGraph) {
-			roots[i] = pomResult
-		})
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
 	}
-
-	return roots
+	var zero T
+	return zero, false
 }
 
 // TODO: 优化gradle解析
@@ -99,6 +441,8 @@ func ParseGradle(ctx context.Context, files []*model.File) []*model.DepGraph {
 var regexs = []*regexp.Regexp{
 	regexp.MustCompile(`group: ?['"]([a-zA-Z]+[^\s"']+)['"], ?name: ?['"]([a-zA-Z]+[^\s"']+)['"], ?version: ?['"]([^\s"']+)['"]`),
 	regexp.MustCompile(`group: ?['"]([a-zA-Z]+[^\s"']+)['"], ?module: ?['"]([a-zA-Z]+[^\s"']+)['"], ?version: ?['"]([^\s"']+)['"]`),
+	// kotlin dsl: group = "g", name = "a", version = "v"
+	regexp.MustCompile(`group\s*=\s*"([a-zA-Z]+[^\s"]+)",\s*name\s*=\s*"([a-zA-Z]+[^\s"]+)",\s*version\s*=\s*"([^\s"]+)"`),
 	regexp.MustCompile(`['"]([a-zA-Z]+[^\s:'"]+):([a-zA-Z]+[^\s:'"]+):([^\s:'"]+)['"]`),
 }
 
@@ -107,10 +451,12 @@ var openscaGradle []byte
 
 // gradle 脚本输出的依赖结构
 type gradleDep struct {
-	GroupId    string       `json:"groupId"`
-	ArtifactId string       `json:"artifactId"`
-	Version    string       `json:"version"`
-	Children   []*gradleDep `json:"children"`
+	GroupId    string `json:"groupId"`
+	ArtifactId string `json:"artifactId"`
+	Version    string `json:"version"`
+	// 引入该组件的configuration
+	Configuration string       `json:"configuration"`
+	Children      []*gradleDep `json:"children"`
 }
 
 func GradleTree(ctx context.Context, dir *model.File) []*model.DepGraph {
@@ -173,11 +519,21 @@ func GradleTree(ctx context.Context, dir *model.File) []*model.DepGraph {
 				if dep.Expand == nil {
 					dep.Expand = c
 				}
+				// 同一组件被多个configuration引入时保留优先级高的作用域
+				dep.Scope = dep.Scope.Merge(gradleScope(c.Configuration))
 				n.AppendChild(dep)
 			}
 			return true
 		})
 
+		// 仅被测试及构建configuration引入的组件标记为开发组件
+		root.ForEachNode(func(p, n *model.DepGraph) bool {
+			if p != nil {
+				n.Develop = n.Scope.Develop()
+			}
+			return true
+		})
+
 		roots = append(roots, root)
 	}
 
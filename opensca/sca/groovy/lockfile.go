@@ -0,0 +1,77 @@
+package groovy
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// GradleLock gradle锁文件中的组件
+type GradleLock struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	// 引入该组件的configuration
+	Configurations []string
+}
+
+// ParseGradleLockfile 解析gradle锁文件
+// https://docs.gradle.org/current/userguide/dependency_locking.html
+func ParseGradleLockfile(file *model.File) []*GradleLock {
+
+	/*
+		# This is a Gradle generated file for dependency locking.
+		com.google.guava:guava:23.0=compileClasspath,runtimeClasspath
+		empty=annotationProcessor
+	*/
+
+	// gradle6以前的锁文件 gradle/dependency-locks/<configuration>.lockfile
+	var conf string
+	if !strings.HasSuffix(file.Relpath(), "gradle.lockfile") {
+		conf = strings.TrimSuffix(filepath.Base(file.Relpath()), ".lockfile")
+	}
+
+	var locks []*GradleLock
+
+	file.ReadLine(func(line string) {
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "empty=") {
+			return
+		}
+
+		lock := &GradleLock{}
+
+		if i := strings.Index(line, "="); i != -1 {
+			for _, c := range strings.Split(line[i+1:], ",") {
+				if c = strings.TrimSpace(c); c != "" {
+					lock.Configurations = append(lock.Configurations, c)
+				}
+			}
+			line = line[:i]
+		} else if conf != "" {
+			lock.Configurations = []string{conf}
+		}
+
+		gav := strings.Split(line, ":")
+		if len(gav) < 3 {
+			return
+		}
+		lock.GroupId, lock.ArtifactId, lock.Version = gav[0], gav[1], gav[2]
+
+		locks = append(locks, lock)
+	})
+
+	return locks
+}
+
+// gradleLockDir 锁文件对应的项目目录
+func gradleLockDir(relpath string) string {
+	dir := filepath.Dir(relpath)
+	// gradle/dependency-locks/*.lockfile
+	if filepath.Base(dir) == "dependency-locks" && filepath.Base(filepath.Dir(dir)) == "gradle" {
+		dir = filepath.Dir(filepath.Dir(dir))
+	}
+	return dir
+}
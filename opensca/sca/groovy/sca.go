@@ -14,7 +14,10 @@ func (sca Sca) Language() model.Language {
 }
 
 func (sca Sca) Filter(relpath string) bool {
-	return filter.GroovyGradle(relpath) || filter.GroovyFile(relpath)
+	return filter.GroovyGradle(relpath) ||
+		filter.GroovyGradleLock(relpath) ||
+		filter.GroovyVersionCatalog(relpath) ||
+		filter.GroovyFile(relpath)
 }
 
 func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
@@ -0,0 +1,92 @@
+package groovy
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// GradleSettings settings.gradle中的项目结构
+type GradleSettings struct {
+	// 根项目名
+	Name string
+	// 包含的子项目 key:项目路径(:lib:core) value:项目目录(相对settings所在目录)
+	Projects map[string]string
+	// pluginManagement中声明的插件版本 key:插件id
+	Plugins map[string]string
+	File    *model.File
+}
+
+var (
+	rootNameReg   = regexp.MustCompile(`rootProject\.name\s*=\s*['"]([^'"]+)['"]`)
+	includeReg    = regexp.MustCompile(`^\s*include\b\s*\(?(.*)`)
+	includeArgReg = regexp.MustCompile(`['"]([^'"]+)['"]`)
+	projectDirReg = regexp.MustCompile(`project\(\s*['"]([^'"]+)['"]\s*\)\.projectDir\s*=\s*(?:file\()?\s*(?:File\([^,]*,\s*)?['"]([^'"]+)['"]`)
+)
+
+// ParseGradleSettings 解析settings.gradle(.kts)
+func ParseGradleSettings(file *model.File) *GradleSettings {
+
+	settings := &GradleSettings{
+		Name:     filepath.Base(filepath.Dir(file.Relpath())),
+		Projects: map[string]string{},
+		Plugins:  map[string]string{},
+		File:     file,
+	}
+
+	file.ReadLineNoComment(model.CTypeComment, func(line string) {
+
+		if m := rootNameReg.FindStringSubmatch(line); len(m) == 2 {
+			settings.Name = m[1]
+			return
+		}
+
+		// pluginManagement { plugins { id 'x' version 'y' } }
+		if m := pluginReg.FindStringSubmatch(line); len(m) == 3 && m[2] != "" {
+			settings.Plugins[m[1]] = m[2]
+			return
+		}
+
+		if m := projectDirReg.FindStringSubmatch(line); len(m) == 3 {
+			settings.Projects[gradleProjectPath(m[1])] = filepath.Clean(m[2])
+			return
+		}
+
+		// include 'app', ':lib:core' | include(":app", ":lib:core")
+		if m := includeReg.FindStringSubmatch(line); len(m) == 2 {
+			for _, arg := range includeArgReg.FindAllStringSubmatch(m[1], -1) {
+				path := gradleProjectPath(arg[1])
+				if _, ok := settings.Projects[path]; !ok {
+					settings.Projects[path] = filepath.Join(strings.Split(strings.TrimPrefix(path, ":"), ":")...)
+				}
+			}
+		}
+	})
+
+	return settings
+}
+
+// gradleProjectPath 规范化项目路径 lib:core=>:lib:core
+func gradleProjectPath(path string) string {
+	return ":" + strings.TrimPrefix(strings.TrimSpace(path), ":")
+}
+
+// Project 获取目录对应的项目路径
+// dir: 项目目录(相对于扫描根目录)
+func (s *GradleSettings) Project(dir string) (string, bool) {
+	rel, err := filepath.Rel(filepath.Dir(s.File.Relpath()), dir)
+	if err != nil {
+		return "", false
+	}
+	if rel == "." {
+		return ":", true
+	}
+	for path, pdir := range s.Projects {
+		if filepath.Clean(pdir) == rel {
+			return path, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,205 @@
+package ivy
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/java"
+)
+
+// IvyModule ivy.xml
+// https://ant.apache.org/ivy/history/latest-milestone/ivyfile.html
+type IvyModule struct {
+	Info struct {
+		Organisation string `xml:"organisation,attr"`
+		Module       string `xml:"module,attr"`
+		Revision     string `xml:"revision,attr"`
+	} `xml:"info"`
+	Dependencies struct {
+		DefaultConf  string           `xml:"defaultconf,attr"`
+		Dependencies []*IvyDependency `xml:"dependency"`
+		Excludes     []*IvyExclude    `xml:"exclude"`
+	} `xml:"dependencies"`
+}
+
+// IvyDependency ivy依赖
+type IvyDependency struct {
+	Org        string        `xml:"org,attr"`
+	Name       string        `xml:"name,attr"`
+	Rev        string        `xml:"rev,attr"`
+	Conf       string        `xml:"conf,attr"`
+	Transitive string        `xml:"transitive,attr"`
+	Excludes   []*IvyExclude `xml:"exclude"`
+}
+
+// IvyExclude ivy排除的依赖
+type IvyExclude struct {
+	Org    string `xml:"org,attr"`
+	Module string `xml:"module,attr"`
+}
+
+// ReadIvyModule 读取ivy.xml
+func ReadIvyModule(file *model.File) *IvyModule {
+	ivy := &IvyModule{}
+	file.OpenReader(func(reader io.Reader) {
+		if err := xml.NewDecoder(reader).Decode(ivy); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+	return ivy
+}
+
+// ReadIvySettings 读取ivysettings.xml中配置的maven仓库
+// https://ant.apache.org/ivy/history/latest-milestone/settings.html
+func ReadIvySettings(file *model.File) []string {
+
+	var repos []string
+	props := map[string]string{}
+
+	replace := func(s string) string {
+		for k, v := range props {
+			s = strings.ReplaceAll(s, "${"+k+"}", v)
+		}
+		return s
+	}
+
+	file.OpenReader(func(reader io.Reader) {
+		decoder := xml.NewDecoder(reader)
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				break
+			}
+			elem, ok := token.(xml.StartElement)
+			if !ok {
+				continue
+			}
+			attrs := map[string]string{}
+			for _, attr := range elem.Attr {
+				attrs[attr.Name.Local] = replace(attr.Value)
+			}
+			switch elem.Name.Local {
+			// <property name="repo.url" value="https://repo.example.com/maven2"/>
+			case "property":
+				if _, ok := props[attrs["name"]]; !ok && attrs["name"] != "" {
+					props[attrs["name"]] = attrs["value"]
+				}
+			// <ibiblio name="nexus" m2compatible="true" root="https://repo.example.com/maven2"/>
+			case "ibiblio":
+				if attrs["m2compatible"] == "true" && strings.HasPrefix(attrs["root"], "http") {
+					repos = append(repos, strings.TrimSuffix(attrs["root"], "/"))
+				}
+			}
+		}
+	})
+
+	return repos
+}
+
+// ivyConfs 获取依赖声明的模块配置 compile,runtime->default;test->test => [compile runtime test]
+func ivyConfs(conf string) []string {
+	var confs []string
+	for _, mapping := range strings.Split(conf, ";") {
+		if i := strings.Index(mapping, "->"); i != -1 {
+			mapping = mapping[:i]
+		}
+		for _, c := range strings.Split(mapping, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				confs = append(confs, c)
+			}
+		}
+	}
+	return confs
+}
+
+// ivyScope 将ivy模块配置映射为组件作用域
+func ivyScope(confs []string) model.Scope {
+	scope := model.Scope_None
+	for _, c := range confs {
+		c = strings.ToLower(c)
+		switch {
+		case strings.Contains(c, "test"):
+			scope = scope.Merge(model.Scope_Test)
+		case strings.Contains(c, "runtime"):
+			scope = scope.Merge(model.Scope_Runtime)
+		default:
+			// ivy配置可自定义 未知配置视为编译依赖
+			scope = scope.Merge(model.Scope_Compile)
+		}
+	}
+	if scope == model.Scope_None {
+		scope = model.Scope_Compile
+	}
+	return scope
+}
+
+// ivyRevision 动态版本取一个确定版本 [1.0,2.0] => 1.0
+func ivyRevision(rev string) string {
+	if !strings.ContainsAny(rev, "()[]") {
+		return rev
+	}
+	return strings.TrimSpace(strings.Trim(strings.Split(rev, ",")[0], "()[]"))
+}
+
+// ParseIvy 解析ivy.xml
+// repos: ivysettings.xml中配置的maven仓库
+func ParseIvy(ctx context.Context, file *model.File, repos []string) *model.DepGraph {
+
+	ivy := ReadIvyModule(file)
+
+	pom := &java.Pom{File: file, Repositories: repos}
+	pom.GroupId = ivy.Info.Organisation
+	pom.ArtifactId = ivy.Info.Module
+	pom.Version = ivy.Info.Revision
+
+	// 记录直接依赖的作用域 map[org:name]
+	scopes := map[string]model.Scope{}
+
+	for _, d := range ivy.Dependencies.Dependencies {
+
+		dep := &java.PomDependency{GroupId: d.Org, ArtifactId: d.Name, Version: ivyRevision(d.Rev)}
+
+		conf := d.Conf
+		if conf == "" {
+			conf = ivy.Dependencies.DefaultConf
+		}
+		scope := ivyScope(ivyConfs(conf))
+		if scope.Develop() {
+			dep.Scope = "test"
+		}
+
+		// transitive=false 不引入间接依赖
+		if d.Transitive == "false" {
+			dep.Exclusions = append(dep.Exclusions, &java.PomDependency{GroupId: "*", ArtifactId: "*"})
+		}
+		for _, e := range append(d.Excludes, ivy.Dependencies.Excludes...) {
+			dep.Exclusions = append(dep.Exclusions, &java.PomDependency{GroupId: e.Org, ArtifactId: e.Module})
+		}
+
+		key := d.Org + ":" + d.Name
+		scopes[key] = scopes[key].Merge(scope)
+		pom.Dependencies = append(pom.Dependencies, dep)
+	}
+
+	var root *model.DepGraph
+	java.ParsePoms(ctx, []*java.Pom{pom}, nil, func(pom *java.Pom, pomResult *model.DepGraph) {
+		pomResult.ForEachNode(func(p, n *model.DepGraph) bool {
+			if p == nil {
+				return true
+			}
+			if s, ok := scopes[n.Vendor+":"+n.Name]; ok && p == pomResult {
+				n.Scope = s
+			} else if n.Scope == model.Scope_None {
+				n.Scope = p.Scope
+			}
+			return true
+		})
+		root = pomResult
+	})
+
+	return root
+}
@@ -0,0 +1,50 @@
+package ivy
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+type Sca struct{}
+
+func (sca Sca) Language() model.Language {
+	return model.Lan_Java
+}
+
+func (sca Sca) Filter(relpath string) bool {
+	return filter.JavaIvy(relpath) || filter.JavaIvySettings(relpath)
+}
+
+func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
+
+	// ivysettings.xml中配置的仓库 map[dir]
+	settings := map[string][]string{}
+	for _, f := range files {
+		if filter.JavaIvySettings(f.Relpath()) {
+			settings[filepath.Dir(f.Relpath())] = ReadIvySettings(f)
+		}
+	}
+
+	for _, f := range files {
+		if !filter.JavaIvy(f.Relpath()) {
+			continue
+		}
+		// 使用所在目录或上级目录中的ivysettings.xml
+		var repos []string
+		for dir := filepath.Dir(f.Relpath()); ; dir = filepath.Dir(dir) {
+			if r, ok := settings[dir]; ok {
+				repos = r
+				break
+			}
+			if dir == filepath.Dir(dir) {
+				break
+			}
+		}
+		if root := ParseIvy(ctx, f, repos); root != nil {
+			call(f, root)
+		}
+	}
+}
@@ -28,52 +28,7 @@ func ParsePoms(ctx context.Context, poms []*Pom, exclusion []*Pom, call func(pom
 	// modules继承属性
 	inheritModules(poms)
 
-	// 记录当前项目的pom文件信息
-	gavMap := map[string]*model.File{}
-	PathMap := map[string]*model.File{}
-	for _, pom := range poms {
-		gavMap[pom.GAV()] = pom.File
-		pom.Update(&pom.PomDependency)
-		gavMap[pom.GAV()] = pom.File
-		if pom.File.Relpath() != "" {
-			PathMap[pom.File.Relpath()] = pom.File
-		}
-	}
-
-	// 获取dependency对应的pom
-	getpom := func(dep PomDependency, repos ...[]string) *Pom {
-		// 通过gav查找pom
-		f, ok := gavMap[dep.GAV()]
-		// 通过relativaPath查找pom
-		if !ok && dep.RelativePath != "" && dep.Define != nil && dep.Define.File.Relpath() != "" {
-			pompath := filepath.Join(filepath.Dir(dep.Define.File.Relpath()), dep.RelativePath)
-			f, ok = PathMap[pompath]
-		}
-		var p *Pom
-		if ok {
-			f.OpenReader(func(reader io.Reader) {
-				p = ReadPom(reader)
-				p.File = f
-			})
-		}
-		if p != nil {
-			return p
-		}
-		// 从组件仓库下载pom
-		var rs []common.RepoConfig
-		for _, urls := range repos {
-			for _, url := range urls {
-				rs = append(rs, common.RepoConfig{Url: url})
-			}
-		}
-		p = mavenOrigin(dep.GroupId, dep.ArtifactId, dep.Version, rs...)
-
-		if p == nil {
-			logs.Warnf("not found pom %s", dep.Index3())
-		}
-
-		return p
-	}
+	getpom := projectPomGetter(poms)
 
 	exclusionMap := map[*Pom]bool{}
 	for _, pom := range exclusion {
@@ -180,6 +135,82 @@ func inheritModules(poms []*Pom) {
 	})
 }
 
+// projectPomGetter 获取dependency对应的pom 优先使用项目中的pom文件
+func projectPomGetter(poms []*Pom) getPomFunc {
+
+	// 记录当前项目的pom文件信息
+	gavMap := map[string]*model.File{}
+	PathMap := map[string]*model.File{}
+	for _, pom := range poms {
+		gavMap[pom.GAV()] = pom.File
+		pom.Update(&pom.PomDependency)
+		gavMap[pom.GAV()] = pom.File
+		if pom.File.Relpath() != "" {
+			PathMap[pom.File.Relpath()] = pom.File
+		}
+	}
+
+	return func(dep PomDependency, repos ...[]string) *Pom {
+		// 通过gav查找pom
+		f, ok := gavMap[dep.GAV()]
+		// 通过relativaPath查找pom
+		if !ok && dep.RelativePath != "" && dep.Define != nil && dep.Define.File.Relpath() != "" {
+			pompath := filepath.Join(filepath.Dir(dep.Define.File.Relpath()), dep.RelativePath)
+			f, ok = PathMap[pompath]
+		}
+		var p *Pom
+		if ok {
+			f.OpenReader(func(reader io.Reader) {
+				p = ReadPom(reader)
+				p.File = f
+			})
+		}
+		if p != nil {
+			return p
+		}
+		// 从组件仓库下载pom
+		var rs []common.RepoConfig
+		for _, urls := range repos {
+			for _, url := range urls {
+				rs = append(rs, common.RepoConfig{Url: url})
+			}
+		}
+		p = mavenOrigin(dep.GroupId, dep.ArtifactId, dep.Version, rs...)
+
+		if p == nil {
+			logs.Warnf("not found pom %s", dep.Index3())
+		}
+
+		return p
+	}
+}
+
+// AppendPomPlugins 为mvn dependency:tree的解析结果添加构建插件及扩展
+// mvn dependency:tree的输出中不包含构建插件及扩展
+// poms: 项目中全部的pom文件列表
+// trees: mvn解析成功的pom及其依赖图
+func AppendPomPlugins(poms []*Pom, trees map[*Pom]*model.DepGraph) {
+
+	if len(trees) == 0 {
+		return
+	}
+
+	// modules继承属性
+	inheritModules(poms)
+
+	getpom := projectPomGetter(poms)
+
+	for pom, root := range trees {
+		if pom.Properties == nil {
+			pom.Properties = PomProperties{}
+		}
+		pom.Update(&pom.PomDependency)
+		// 继承parent中的插件及属性
+		inheritPom(pom, getpom)
+		appendPlugins(root, pom, getpom)
+	}
+}
+
 type getPomFunc func(dep PomDependency, repos ...[]string) *Pom
 
 // inheritPom 继承pom所需内容
@@ -222,8 +253,14 @@ func inheritPom(pom *Pom, getpom getPomFunc) {
 
 		// 继承repo&mirror
 		pom.Repositories = append(pom.Repositories, parentPom.Repositories...)
+		pom.PluginRepositories = append(pom.PluginRepositories, parentPom.PluginRepositories...)
 		pom.Mirrors = append(pom.Mirrors, parentPom.Mirrors...)
 
+		// 继承构建插件及扩展
+		pom.Plugins = append(pom.Plugins, parentPom.Plugins...)
+		pom.PluginManagement = append(pom.PluginManagement, parentPom.PluginManagement...)
+		pom.Extensions = append(pom.Extensions, parentPom.Extensions...)
+
 	}
 
 	// 更新pom坐标
@@ -390,11 +427,11 @@ func parsePom(ctx context.Context, pom *Pom, getpom getPomFunc) *model.DepGraph
 				continue
 			}
 
-			// 保留先声明的组件
-			if depIndex2Set[dep.Index2()] {
+			// 保留先声明的组件 构建插件的依赖与项目依赖分开统计
+			if depIndex2Set[string(n.Scope)+dep.Index2()] {
 				continue
 			}
-			depIndex2Set[dep.Index2()] = true
+			depIndex2Set[string(n.Scope)+dep.Index2()] = true
 
 			if dep.Check() {
 				logs.Debugf("find %s", dep.ImportPathStack())
@@ -405,6 +442,9 @@ func parsePom(ctx context.Context, pom *Pom, getpom getPomFunc) *model.DepGraph
 
 			sub := &model.DepGraph{Vendor: dep.GroupId, Name: dep.ArtifactId, Version: dep.Version}
 			sub.Develop = dep.Scope == "test"
+			if n.Scope == model.Scope_Plugin {
+				sub.Scope = model.Scope_Plugin
+			}
 
 			if subpom := getpom(*dep, np.Repositories, np.Mirrors); subpom != nil {
 				subpom.PomDependency = *dep
@@ -418,12 +458,70 @@ func parsePom(ctx context.Context, pom *Pom, getpom getPomFunc) *model.DepGraph
 			n.AppendChild(sub)
 		}
 
+		// 根pom引入的构建插件及扩展
+		if np == pom {
+			appendPlugins(n, pom, getpom)
+		}
+
 		return true
 	})
 
+	root.AppendRuntime(model.Runtime_Jdk, pom.JdkVersion())
+
 	return root
 }
 
+// appendPlugins 添加pom中使用的构建插件及扩展
+func appendPlugins(root *model.DepGraph, pom *Pom, getpom getPomFunc) {
+
+	// 记录pluginManagement中的插件版本
+	// 插件可能与parent及其他模块共享 复制后再解析属性
+	pluginManagement := map[string]*PomDependency{}
+	for _, p := range pom.PluginManagement {
+		plugin := &PomDependency{}
+		*plugin = *p
+		pom.Update(plugin)
+		if _, ok := pluginManagement[plugin.Index2()]; !ok {
+			pluginManagement[plugin.Index2()] = plugin
+		}
+	}
+
+	repos := append(append([]string{}, pom.PluginRepositories...), pom.Repositories...)
+
+	pluginSet := map[string]bool{}
+	for _, p := range append(pom.Plugins, pom.Extensions...) {
+
+		plugin := &PomDependency{}
+		*plugin = *p
+		pom.Update(plugin)
+
+		if plugin.Version == "" {
+			if d, ok := pluginManagement[plugin.Index2()]; ok {
+				plugin.Version = d.Version
+			}
+		}
+
+		if pluginSet[plugin.Index2()] {
+			continue
+		}
+		pluginSet[plugin.Index2()] = true
+
+		// 未指定版本的插件使用maven内置版本 无法确定具体版本
+		if !plugin.Check() {
+			logs.Debugf("skip plugin %s", plugin.ImportPathStack())
+			continue
+		}
+
+		sub := &model.DepGraph{Vendor: plugin.GroupId, Name: plugin.ArtifactId, Version: plugin.Version, Scope: model.Scope_Plugin}
+		if subpom := getpom(*plugin, repos, pom.Mirrors); subpom != nil {
+			subpom.PomDependency = *plugin
+			inheritPom(subpom, getpom)
+			sub.Expand = subpom
+		}
+		root.AppendChild(sub)
+	}
+}
+
 var mavenOrigin = func(groupId, artifactId, version string, repos ...common.RepoConfig) *Pom {
 
 	var p *Pom
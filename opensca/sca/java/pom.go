@@ -20,6 +20,10 @@ type Pom struct {
 	Dependencies         []*PomDependency `xml:"dependencies>dependency"`
 	Modules              []string         `xml:"modules>module"`
 	Repositories         []string         `xml:"repositories>repository>url"`
+	PluginRepositories   []string         `xml:"pluginRepositories>pluginRepository>url"`
+	Plugins              []*PomDependency `xml:"build>plugins>plugin"`
+	PluginManagement     []*PomDependency `xml:"build>pluginManagement>plugins>plugin"`
+	Extensions           []*PomDependency `xml:"build>extensions>extension"`
 	Mirrors              []string         `xml:"mirrors>mirror>url"`
 	Licenses             []string         `xml:"licenses>license>name"`
 	Profiles             []Pom            `xml:"profiles>profile"`
@@ -164,6 +168,16 @@ func ReadPom(reader io.Reader) *Pom {
 		trimSpace(d)
 		d.Define = p
 	}
+	for _, d := range append(append(p.Plugins, p.PluginManagement...), p.Extensions...) {
+		trimSpace(d)
+		d.Define = p
+	}
+	// 插件groupId缺省值
+	for _, d := range append(p.Plugins, p.PluginManagement...) {
+		if d.GroupId == "" {
+			d.GroupId = "org.apache.maven.plugins"
+		}
+	}
 
 	// 添加内置属性
 	p.Properties["project.groupId"] = &Property{Key: "project.groupId", Value: p.GroupId}
@@ -225,6 +239,27 @@ func ReadPom(reader io.Reader) *Pom {
 	return p
 }
 
+// ReadMvnExtensions 读取.mvn/extensions.xml中的构建扩展
+func ReadMvnExtensions(reader io.Reader) []*PomDependency {
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		logs.Warn(err)
+		return nil
+	}
+
+	ext := struct {
+		Extensions []*PomDependency `xml:"extension"`
+	}{}
+	xml.Unmarshal(data, &ext)
+
+	for _, d := range ext.Extensions {
+		trimSpace(d)
+	}
+
+	return ext.Extensions
+}
+
 // Update 使用pom信息更新当前依赖中使用的属性
 func (p *Pom) Update(dep *PomDependency) {
 	var ref *Property
@@ -238,6 +273,22 @@ func (p *Pom) Update(dep *PomDependency) {
 	}
 }
 
+// JdkVersion 项目使用的jdk版本 1.8记为8
+func (p *Pom) JdkVersion() string {
+	for _, key := range []string{"maven.compiler.release", "maven.compiler.target", "maven.compiler.source", "java.version"} {
+		v, ok := p.Properties[key]
+		if !ok {
+			continue
+		}
+		version, _ := p.update(v.Value)
+		version = strings.TrimSpace(version)
+		if version != "" && !strings.Contains(version, "${") {
+			return strings.TrimPrefix(version, "1.")
+		}
+	}
+	return ""
+}
+
 var propertyReg = regexp.MustCompile(`\$\{[^{}]*\}`)
 
 // update 使用pom信息更新字符串中使用的属性
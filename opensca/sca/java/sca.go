@@ -3,6 +3,7 @@ package java
 import (
 	"context"
 	"io"
+	"path/filepath"
 	"strings"
 
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/common"
@@ -22,7 +23,7 @@ func (sca Sca) Language() model.Language {
 }
 
 func (sca Sca) Filter(relpath string) bool {
-	return filter.JavaPom(relpath)
+	return filter.JavaPom(relpath) || filter.JavaMvnExtensions(relpath)
 }
 
 func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
@@ -64,18 +65,43 @@ func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File,
 		}
 	}
 
+	// 记录.mvn/extensions.xml中的构建扩展 map[项目目录]
+	extensions := map[string][]*PomDependency{}
+	for _, file := range files {
+		if filter.JavaMvnExtensions(file.Relpath()) {
+			file.OpenReader(func(reader io.Reader) {
+				dir := filepath.Dir(filepath.Dir(file.Relpath()))
+				extensions[dir] = append(extensions[dir], ReadMvnExtensions(reader)...)
+			})
+		}
+	}
+	for _, pom := range poms {
+		if ext, ok := extensions[filepath.Dir(pom.File.Relpath())]; ok {
+			for _, e := range ext {
+				e.Define = pom
+			}
+			pom.Extensions = append(pom.Extensions, ext...)
+		}
+	}
+
 	// 记录不需要静态解析的pom
 	var exclusionPom []*Pom
 
 	// 优先尝试调用mvn
 	if !sca.NotUseMvn {
+		trees := map[*Pom]*model.DepGraph{}
 		for _, pom := range poms {
-			dep := MvnTree(ctx, pom)
-			if dep != nil {
-				call(pom.File, dep)
+			if dep := MvnTree(ctx, pom); dep != nil {
+				trees[pom] = dep
 				exclusionPom = append(exclusionPom, pom)
 			}
 		}
+		AppendPomPlugins(poms, trees)
+		for _, pom := range exclusionPom {
+			dep := trees[pom]
+			dep.AppendRuntime(model.Runtime_Jdk, pom.JdkVersion())
+			call(pom.File, dep)
+		}
 	}
 
 	// 静态解析
@@ -0,0 +1,88 @@
+package javascript
+
+import "github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+
+// lockEdge 锁文件中记录的依赖关系
+type lockEdge struct {
+	// 子依赖组件标识
+	id string
+	// 是否为可选依赖
+	optional bool
+}
+
+// lockGraph 锁文件中记录的依赖关系图
+type lockGraph struct {
+	// 组件节点 key:组件标识
+	nodes map[string]*model.DepGraph
+	// 依赖关系 key:组件标识
+	edges map[string][]lockEdge
+}
+
+func newLockGraph() *lockGraph {
+	return &lockGraph{
+		nodes: map[string]*model.DepGraph{},
+		edges: map[string][]lockEdge{},
+	}
+}
+
+// node 获取组件节点 不存在时创建
+func (g *lockGraph) node(id, name, version string) *model.DepGraph {
+	if _, ok := g.nodes[id]; !ok {
+		g.nodes[id] = &model.DepGraph{Name: name, Version: version}
+	}
+	return g.nodes[id]
+}
+
+// reach 从starts开始可到达的组件
+// required: 是否仅统计非可选依赖
+func (g *lockGraph) reach(required bool, starts ...[]lockEdge) map[string]bool {
+	set := map[string]bool{}
+	var q []string
+	for _, es := range starts {
+		for _, e := range es {
+			if !(required && e.optional) {
+				q = append(q, e.id)
+			}
+		}
+	}
+	for len(q) > 0 {
+		id := q[0]
+		q = q[1:]
+		if set[id] {
+			continue
+		}
+		set[id] = true
+		for _, e := range g.edges[id] {
+			if !(required && e.optional) {
+				q = append(q, e.id)
+			}
+		}
+	}
+	return set
+}
+
+// build 构建依赖图 并根据可达性标记开发组件及可选组件
+// prod/optional/dev: 根节点的直接依赖
+func (g *lockGraph) build(root *model.DepGraph, prod, optional, dev []lockEdge) *model.DepGraph {
+
+	// 非开发组件
+	prodSet := g.reach(false, prod, optional)
+	// 非可选组件
+	requiredSet := g.reach(true, prod, dev)
+
+	for id, dep := range g.nodes {
+		dep.Develop = !prodSet[id]
+		dep.Optional = !requiredSet[id]
+		for _, e := range g.edges[id] {
+			dep.AppendChild(g.nodes[e.id])
+		}
+	}
+
+	for _, es := range [][]lockEdge{prod, optional, dev} {
+		for _, e := range es {
+			root.AppendChild(g.nodes[e.id])
+		}
+	}
+
+	return root
+}
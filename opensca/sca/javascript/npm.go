@@ -20,8 +20,15 @@ type PackageJson struct {
 	Version string `json:"version"`
 	// License              string            `json:"license"`
 	Develop bool `json:"dev"` // lock v3
-	// TODO 只有依赖冲突时才会使用
-	Resolutions          map[string]string `json:"resolutions"`
+	// lock v2/v3 中workspace的链接 resolved为workspace路径
+	Link     bool   `json:"link"`
+	Resolved string `json:"resolved"`
+	// 版本覆盖规则 npm: overrides yarn: resolutions pnpm: pnpm.overrides
+	Overrides   json.RawMessage   `json:"overrides"`
+	Resolutions map[string]string `json:"resolutions"`
+	Pnpm        struct {
+		Overrides map[string]string `json:"overrides"`
+	} `json:"pnpm"`
 	Dependencies         map[string]string `json:"dependencies"`
 	DevDependencies      map[string]string `json:"devDependencies"`
 	PeerDependencies     map[string]string `json:"peerDependencies"`
@@ -29,7 +36,18 @@ type PackageJson struct {
 	PeerDependenciesMeta map[string]struct {
 		Optional bool `json:"optional"`
 	} `json:"peerDependenciesMeta"`
-	File *model.File `json:"-"`
+	// 运行环境 早期版本中可能为数组
+	Engines json.RawMessage `json:"engines"`
+	File    *model.File     `json:"-"`
+}
+
+// NodeVersion package.json中声明的node版本约束
+func (js *PackageJson) NodeVersion() string {
+	var engines map[string]string
+	if json.Unmarshal(js.Engines, &engines) != nil {
+		return ""
+	}
+	return strings.TrimSpace(engines["node"])
 }
 
 type NpmJson struct {
@@ -120,8 +138,13 @@ func ParsePackageJsonWithNode(pkgjson *PackageJson, nodeMap map[string]*PackageJ
 
 	_dep := _depSet().LoadOrStore
 
-	findDep := func(dev bool, name, version, basedir string) *model.DepGraph {
-		var subjs *PackageJson
+	// 查找依赖对应的package.json
+	// local: 是否引用项目中的其他package.json
+	lookup := func(name, version, basedir string) (subjs *PackageJson, local bool) {
+		if pkg, ok := pkgMap[name]; ok && isLocalSpec(version) {
+			// workspace:/file:/link:协议直接使用项目中的package.json
+			subjs, local = pkg, true
+		}
 		if subjs == nil && len(nodeMap) > 0 {
 			// 从node_modules中查找
 			_, subjs = findFromNodeModules(name, basedir, nodeMap)
@@ -132,7 +155,7 @@ func ParsePackageJsonWithNode(pkgjson *PackageJson, nodeMap map[string]*PackageJ
 				if pkg, ok := pkgMap[name]; ok {
 					if v, err := semver.NewVersion(pkg.Version); err == nil {
 						if c.Check(v) {
-							subjs = pkg
+							subjs, local = pkg, true
 						}
 					}
 				}
@@ -149,6 +172,19 @@ func ParsePackageJsonWithNode(pkgjson *PackageJson, nodeMap map[string]*PackageJ
 				Version: version,
 			}
 		}
+		return
+	}
+
+	// 每个组件的依赖中生效的覆盖规则
+	active := map[*model.DepGraph]overrideRules{}
+
+	findDep := func(dev bool, name, version, basedir string, rules overrideRules) *model.DepGraph {
+		subjs, local := lookup(name, version, basedir)
+		// 应用overrides/resolutions
+		rule := rules.match(name, func() string { return subjs.Version })
+		if rule != nil && rule.Version != "" && rule.Version != version {
+			subjs, local = lookup(name, rule.Version, basedir)
+		}
 		var dep *model.DepGraph
 		if dev {
 			dep = _dep(subjs.Name, subjs.Version, "dev")
@@ -159,23 +195,31 @@ func ParsePackageJsonWithNode(pkgjson *PackageJson, nodeMap map[string]*PackageJ
 			// dep.AppendLicense(subjs.License)
 			dep.Expand = subjs
 		}
+		dep.Local = local
+		if rule != nil && rule.Version != "" {
+			dep.Override = rule.Version
+		}
+		if _, ok := active[dep]; !ok {
+			active[dep] = rules.sub(rule)
+		}
 		return dep
 	}
 
 	root := &model.DepGraph{Name: pkgjson.Name, Version: pkgjson.Version, Path: pkgjson.File.Relpath()}
 	// root.AppendLicense(pkgjson.License)
 	root.Expand = pkgjson
+	active[root] = readOverrides(pkgjson)
 
 	// 根节点需要添加开发组件 (需要在构建依赖图之前先添加开发组件 否则不会构建开发组件的子依赖)
 	for name, version := range pkgjson.DevDependencies {
-		root.AppendChild(findDep(true, name, version, pkgjson.File.Relpath()))
+		root.AppendChild(findDep(true, name, version, pkgjson.File.Relpath(), active[root]))
 	}
 
 	// 遍历*路径*构建依赖图
 	root.ForEachPath(func(p, n *model.DepGraph) bool {
 		js := n.Expand.(*PackageJson)
 		for name, version := range js.Dependencies {
-			n.AppendChild(findDep(false, name, version, js.File.Relpath()))
+			n.AppendChild(findDep(false, name, version, js.File.Relpath(), active[n]))
 		}
 		return true
 	})
@@ -268,6 +312,13 @@ func ParsePackageJsonWithLockV3(pkgjson *PackageJson, pkglock *PackageLock) *mod
 		return nil
 	}
 
+	return ParsePackageJsonWithLockPackages(pkgjson, pkglock, "")
+}
+
+// ParsePackageJsonWithLockPackages 借助package.lock(v2/v3)中的packages解析package.json
+// workspace: package.json所在目录相对package.lock的路径 根项目为空
+func ParsePackageJsonWithLockPackages(pkgjson *PackageJson, pkglock *PackageLock, workspace string) *model.DepGraph {
+
 	for jspath, js := range pkglock.Packages {
 		if js.File == nil {
 			js.File = model.NewFile("", jspath)
@@ -281,7 +332,7 @@ func ParsePackageJsonWithLockV3(pkgjson *PackageJson, pkglock *PackageLock) *mod
 		js   *PackageJson
 		path string
 	}
-	root.Expand = expand{js: pkgjson, path: ""}
+	root.Expand = expand{js: pkgjson, path: workspace}
 
 	_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph { return &model.DepGraph{Name: s[0], Version: s[1]} }).LoadOrStore
 
@@ -290,7 +341,19 @@ func ParsePackageJsonWithLockV3(pkgjson *PackageJson, pkglock *PackageLock) *mod
 		if subjs == nil {
 			return nil
 		}
+		// workspace中的其他项目
+		local := subjs.Link
+		if local {
+			jspath = subjs.Resolved
+			if subjs = pkglock.Packages[jspath]; subjs == nil {
+				return nil
+			}
+			if subjs.Name != "" {
+				name = subjs.Name
+			}
+		}
 		dep := _dep(name, subjs.Version, subjs.File.Relpath())
+		dep.Local = local
 		// dep.AppendLicense(subjs.License)
 		if dep.Expand == nil {
 			dep.Develop = subjs.Develop
@@ -330,7 +393,11 @@ func ParsePackageJsonWithLockV3(pkgjson *PackageJson, pkglock *PackageLock) *mod
 			n.AppendChild(findDep(name, njs.path))
 		}
 
+		// 仅引入根项目的开发依赖
 		for name := range njs.js.DevDependencies {
+			if n != root {
+				break
+			}
 			dep := findDep(name, njs.path)
 			if dep != nil {
 				dep.Develop = true
@@ -384,6 +451,16 @@ func FindMaxVersion(version string, versions []string) string {
 	return version
 }
 
+// isLocalSpec 是否为引用本地项目的版本约束
+func isLocalSpec(version string) bool {
+	for _, protocol := range []string{"workspace:", "file:", "link:", "portal:"} {
+		if strings.HasPrefix(version, protocol) {
+			return true
+		}
+	}
+	return false
+}
+
 // findFromNodeModules 从node_modules中查找使用的package.json
 // name: 查找的组件名
 // basedir: 当前package.json所在路径
@@ -405,5 +482,10 @@ func findFromNodeModules(name, basedir string, nodePathMap map[string]*PackageJs
 			return jspath, js
 		}
 	}
-	return
+	// workspace中的项目依赖提升至根目录的node_modules
+	jspath = node_modules + "/" + name
+	if js, ok := nodePathMap[jspath]; ok {
+		return jspath, js
+	}
+	return "", nil
 }
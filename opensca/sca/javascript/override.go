@@ -0,0 +1,195 @@
+package javascript
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// npmOverride 版本覆盖规则
+// https://docs.npmjs.com/cli/v10/configuring-npm/package-json#overrides
+// https://classic.yarnpkg.com/lang/en/docs/selective-version-resolutions/
+type npmOverride struct {
+	// 组件名
+	Name string
+	// 版本选择器 foo@1.x 仅覆盖匹配的版本
+	Selector string
+	// 覆盖后的版本 为空时仅限定子规则的作用范围
+	Version string
+	// 仅在该组件的依赖中生效的规则
+	Children overrideRules
+}
+
+// overrideRules 当前依赖路径中生效的覆盖规则
+type overrideRules []*npmOverride
+
+// child 查找名称及选择器相同的子规则 不存在时创建
+func (rules *overrideRules) child(name, selector string) *npmOverride {
+	for _, r := range *rules {
+		if r.Name == name && r.Selector == selector {
+			return r
+		}
+	}
+	r := &npmOverride{Name: name, Selector: selector}
+	*rules = append(*rules, r)
+	return r
+}
+
+// match 查找组件适用的覆盖规则 越深层的规则优先级越高
+// resolve: 获取组件原本的版本 用于匹配版本选择器
+func (rules overrideRules) match(name string, resolve func() string) *npmOverride {
+	for i := len(rules) - 1; i >= 0; i-- {
+		r := rules[i]
+		if r.Name != name {
+			continue
+		}
+		if r.Selector != "" && !satisfies(resolve(), r.Selector) {
+			continue
+		}
+		return r
+	}
+	return nil
+}
+
+// sub 进入组件r的依赖后生效的规则
+func (rules overrideRules) sub(r *npmOverride) overrideRules {
+	if r == nil || len(r.Children) == 0 {
+		return rules
+	}
+	sub := make(overrideRules, 0, len(rules)+len(r.Children))
+	return append(append(sub, rules...), r.Children...)
+}
+
+// satisfies 版本是否满足约束 无法解析时按字符串比较
+func satisfies(version, constraint string) bool {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return version == constraint
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	return c.Check(v)
+}
+
+// readOverrides 读取package.json中的覆盖规则
+// 包括npm的overrides、yarn的resolutions及pnpm.overrides
+func readOverrides(pkgjson *PackageJson) overrideRules {
+	rules := overrideRules{}
+	if pkgjson == nil {
+		return rules
+	}
+	if len(pkgjson.Overrides) > 0 {
+		var overrides map[string]any
+		if err := json.Unmarshal(pkgjson.Overrides, &overrides); err == nil {
+			readNpmOverrides(&rules, overrides, pkgjson)
+		}
+	}
+	readResolutions(&rules, pkgjson.Resolutions, "/")
+	readResolutions(&rules, pkgjson.Pnpm.Overrides, ">")
+	return rules
+}
+
+// readNpmOverrides 读取npm的overrides
+// {"foo": "1.0.0", "bar@2": {".": "2.1.0", "baz": "$baz"}}
+func readNpmOverrides(rules *overrideRules, overrides map[string]any, pkgjson *PackageJson) {
+	for key, value := range overrides {
+		if key == "." {
+			continue
+		}
+		name, selector := splitDescriptor(key)
+		r := rules.child(name, strings.TrimPrefix(selector, "npm:"))
+		switch v := value.(type) {
+		case string:
+			r.Version = overrideRef(v, pkgjson)
+		case map[string]any:
+			if self, ok := v["."].(string); ok {
+				r.Version = overrideRef(self, pkgjson)
+			}
+			readNpmOverrides(&r.Children, v, pkgjson)
+		}
+	}
+}
+
+// readResolutions 读取yarn的resolutions及pnpm的overrides
+// sep: 依赖路径分隔符 yarn: a/**/b pnpm: a>b
+func readResolutions(rules *overrideRules, resolutions map[string]string, sep string) {
+	for key, version := range resolutions {
+		var names []string
+		for _, s := range strings.Split(key, sep) {
+			switch {
+			case s == "**" || s == "":
+				// **代表任意层级 规则对子孙依赖都生效故忽略
+			case sep == "/" && len(names) > 0 && strings.HasPrefix(names[len(names)-1], "@") && !strings.Contains(names[len(names)-1], "/"):
+				// @scope/name
+				names[len(names)-1] += "/" + s
+			default:
+				names = append(names, s)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		// a/b 转换为a的子规则b
+		cur := rules
+		var r *npmOverride
+		for _, n := range names {
+			if r != nil {
+				cur = &r.Children
+			}
+			r = cur.child(overrideName(n))
+		}
+		r.Version = strings.TrimPrefix(version, "npm:")
+	}
+}
+
+// overrideName 解析规则中的组件名及版本选择器
+func overrideName(descriptor string) (name, selector string) {
+	name, selector = splitDescriptor(descriptor)
+	return name, strings.TrimPrefix(selector, "npm:")
+}
+
+// overrideRef 解析$引用 $foo代表使用根项目中foo的版本
+func overrideRef(version string, pkgjson *PackageJson) string {
+	if !strings.HasPrefix(version, "$") {
+		return version
+	}
+	name := version[1:]
+	for _, deps := range []map[string]string{
+		pkgjson.Dependencies,
+		pkgjson.DevDependencies,
+		pkgjson.OptionalDependencies,
+		pkgjson.PeerDependencies,
+	} {
+		if v, ok := deps[name]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// markOverrides 标记依赖图中受覆盖规则影响的组件
+// 锁文件中记录的已是覆盖后的版本 仅需记录规则
+func markOverrides(root *model.DepGraph, rules overrideRules) {
+	if len(rules) == 0 {
+		return
+	}
+	active := map[*model.DepGraph]overrideRules{root: rules}
+	root.ForEachPath(func(p, n *model.DepGraph) bool {
+		if p == nil {
+			return true
+		}
+		rs := active[p]
+		r := rs.match(n.Name, func() string { return n.Version })
+		if r != nil && r.Version != "" && satisfies(n.Version, r.Version) {
+			n.Override = r.Version
+		}
+		if _, ok := active[n]; !ok {
+			active[n] = rs.sub(r)
+		}
+		return true
+	})
+}
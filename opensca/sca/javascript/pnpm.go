@@ -0,0 +1,236 @@
+package javascript
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"gopkg.in/yaml.v3"
+)
+
+// PnpmLock pnpm-lock.yaml
+// https://github.com/pnpm/spec/tree/master/lockfile
+type PnpmLock struct {
+	LockfileVersion string `yaml:"lockfileVersion"`
+	// 非workspace项目的依赖位于根节点
+	PnpmImporter `yaml:",inline"`
+	// workspace中每个项目的依赖 key:项目相对lock文件的路径
+	Importers map[string]*PnpmImporter `yaml:"importers"`
+	// key: v5 /name/version_peer v6 /name@version(peer) v9 name@version
+	Packages map[string]*PnpmPackage `yaml:"packages"`
+	// v9中组件的依赖关系
+	Snapshots map[string]*PnpmPackage `yaml:"snapshots"`
+}
+
+// PnpmImporter pnpm项目的直接依赖 value:依赖对应的组件引用
+type PnpmImporter struct {
+	Dependencies         map[string]pnpmRef `yaml:"dependencies"`
+	DevDependencies      map[string]pnpmRef `yaml:"devDependencies"`
+	OptionalDependencies map[string]pnpmRef `yaml:"optionalDependencies"`
+}
+
+// PnpmPackage pnpm锁定的组件
+type PnpmPackage struct {
+	Name                 string            `yaml:"name"`
+	Version              string            `yaml:"version"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// pnpmRef 组件引用 v5: 1.0.0 v6/v9: {specifier: ^1.0.0, version: 1.0.0}
+type pnpmRef string
+
+func (ref *pnpmRef) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*ref = pnpmRef(value.Value)
+		return nil
+	}
+	v := struct {
+		Version string `yaml:"version"`
+	}{}
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	*ref = pnpmRef(v.Version)
+	return nil
+}
+
+// ParsePnpmLock 解析pnpm-lock.yaml
+func ParsePnpmLock(file *model.File) *PnpmLock {
+	var lock *PnpmLock
+	file.OpenReader(func(reader io.Reader) {
+		lock = &PnpmLock{}
+		if err := yaml.NewDecoder(reader).Decode(lock); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+			lock = nil
+		}
+	})
+	if lock == nil {
+		return nil
+	}
+	// 非workspace项目
+	if lock.Importers == nil {
+		lock.Importers = map[string]*PnpmImporter{".": &lock.PnpmImporter}
+	}
+	return lock
+}
+
+// major lock文件主版本号
+func (lock *PnpmLock) major() int {
+	v, _ := strconv.ParseFloat(lock.LockfileVersion, 64)
+	return int(v)
+}
+
+// trimPeer 去除版本号中的peer依赖后缀 1.0.0(react@18.2.0) | 1.0.0_react@18.2.0 => 1.0.0
+func trimPeer(version string) string {
+	if i := strings.Index(version, "("); i != -1 {
+		version = version[:i]
+	}
+	if i := strings.Index(version, "_"); i != -1 {
+		version = version[:i]
+	}
+	return version
+}
+
+// key 组件引用对应的packages中的key
+func (lock *PnpmLock) key(name, ref string) string {
+	switch {
+	case lock.major() >= 9:
+		// 别名引用 string-width@4.2.3
+		if v := trimPeer(ref); strings.LastIndex(v, "@") > 0 {
+			return ref
+		}
+		return name + "@" + ref
+	case strings.HasPrefix(ref, "/") || strings.ContainsAny(trimPeer(ref), "/:"):
+		return ref
+	case lock.major() >= 6:
+		return "/" + name + "@" + ref
+	default:
+		return "/" + name + "/" + ref
+	}
+}
+
+// pkg 获取组件名称及版本号
+func (lock *PnpmLock) pkg(key string) (name, version string) {
+
+	if pkg, ok := lock.Packages[key]; ok && pkg.Name != "" && pkg.Version != "" {
+		return pkg.Name, pkg.Version
+	}
+
+	k := strings.TrimPrefix(key, "/")
+	if i := strings.Index(k, "("); i != -1 {
+		k = k[:i]
+	}
+
+	if lock.major() >= 6 {
+		if pkg, ok := lock.Packages[k]; ok && pkg.Name != "" && pkg.Version != "" {
+			return pkg.Name, pkg.Version
+		}
+		if i := strings.LastIndex(k, "@"); i > 0 {
+			return k[:i], k[i+1:]
+		}
+		return k, ""
+	}
+
+	// v5: name/version_peer @scope/name/version_peer
+	s := strings.Split(k, "/")
+	if strings.HasPrefix(k, "@") && len(s) >= 3 {
+		return s[0] + "/" + s[1], trimPeer(s[2])
+	}
+	if len(s) >= 2 {
+		return s[0], trimPeer(s[1])
+	}
+	return k, ""
+}
+
+// deps 组件的依赖
+func (lock *PnpmLock) deps(key string) *PnpmPackage {
+	if pkg, ok := lock.Snapshots[key]; ok {
+		return pkg
+	}
+	if pkg, ok := lock.Packages[key]; ok {
+		return pkg
+	}
+	return &PnpmPackage{}
+}
+
+// ParsePackageJsonWithPnpmLock 借助pnpm-lock.yaml解析package.json
+// importer: package.json所在目录相对pnpm-lock.yaml的路径
+// workspace: workspace中的其他项目 key:项目相对pnpm-lock.yaml的路径
+func ParsePackageJsonWithPnpmLock(pkgjson *PackageJson, lock *PnpmLock, importer string, workspace map[string]*PackageJson) *model.DepGraph {
+
+	root := &model.DepGraph{Name: pkgjson.Name, Version: pkgjson.Version, Path: pkgjson.File.Relpath()}
+
+	imp, ok := lock.Importers[importer]
+	if !ok {
+		return root
+	}
+
+	g := newLockGraph()
+	// 记录已解析的组件引用 key:packages中的key
+	visited := map[string]bool{}
+
+	// 记录引用对应的组件 返回组件标识
+	var resolve func(base, name, ref string) string
+	resolve = func(base, name, ref string) string {
+
+		// workspace中的其他项目
+		if strings.HasPrefix(ref, "link:") {
+			dir := path.Clean(path.Join(base, strings.TrimPrefix(ref, "link:")))
+			id := "link:" + dir
+			if _, ok := g.nodes[id]; ok {
+				return id
+			}
+			dep := g.node(id, name, "")
+			dep.Local = true
+			if js, ok := workspace[dir]; ok {
+				dep.Name, dep.Version = js.Name, js.Version
+			}
+			if sub, ok := lock.Importers[dir]; ok {
+				for n, r := range sub.Dependencies {
+					g.edges[id] = append(g.edges[id], lockEdge{resolve(dir, n, string(r)), false})
+				}
+				for n, r := range sub.OptionalDependencies {
+					g.edges[id] = append(g.edges[id], lockEdge{resolve(dir, n, string(r)), true})
+				}
+			}
+			return id
+		}
+
+		key := lock.key(name, ref)
+		n, v := lock.pkg(key)
+		id := fmt.Sprintf("%s@%s", n, v)
+		g.node(id, n, v)
+
+		// 同一组件的不同peer依赖版本合并为一个节点
+		if visited[key] {
+			return id
+		}
+		visited[key] = true
+		pkg := lock.deps(key)
+		for n, r := range pkg.Dependencies {
+			g.edges[id] = append(g.edges[id], lockEdge{resolve(base, n, r), false})
+		}
+		for n, r := range pkg.OptionalDependencies {
+			g.edges[id] = append(g.edges[id], lockEdge{resolve(base, n, r), true})
+		}
+		return id
+	}
+
+	var prod, dev, optional []lockEdge
+	for n, r := range imp.Dependencies {
+		prod = append(prod, lockEdge{resolve(importer, n, string(r)), false})
+	}
+	for n, r := range imp.OptionalDependencies {
+		optional = append(optional, lockEdge{resolve(importer, n, string(r)), true})
+	}
+	for n, r := range imp.DevDependencies {
+		dev = append(dev, lockEdge{resolve(importer, n, string(r)), false})
+	}
+
+	return g.build(root, prod, optional, dev)
+}
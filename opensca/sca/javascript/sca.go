@@ -3,6 +3,7 @@ package javascript
 import (
 	"context"
 	"io"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -20,7 +21,8 @@ func (sca Sca) Language() model.Language {
 func (sca Sca) Filter(relpath string) bool {
 	return filter.JavaScriptPackageJson(relpath) ||
 		filter.JavaScriptPackageLock(relpath) ||
-		filter.JavaScriptYarnLock(relpath)
+		filter.JavaScriptYarnLock(relpath) ||
+		filter.JavaScriptPnpmLock(relpath)
 }
 
 func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
@@ -30,10 +32,15 @@ func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File,
 	jsonNameMap := map[string]*PackageJson{}
 	// map[dirpath]
 	lockMap := map[string]*PackageLock{}
+	shrinkwrapSet := map[string]bool{}
 	// map[dirpath]
 	nodeMap := map[string]*PackageJson{}
 	// map[dirpath]
 	yarnMap := map[string]map[string]*YarnLock{}
+	// map[dirpath]
+	berryMap := map[string]*YarnBerryLock{}
+	// map[dirpath]
+	pnpmMap := map[string]*PnpmLock{}
 
 	// 将npm相关文件按上述方案分类
 	for _, f := range files {
@@ -41,7 +48,17 @@ func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File,
 		dir := filepath.Dir(strings.ReplaceAll(f.Relpath(), `\`, `/`))
 
 		if filter.JavaScriptYarnLock(f.Relpath()) {
-			yarnMap[dir] = ParseYarnLock(f)
+			if berry := ParseYarnBerryLock(f); berry != nil {
+				berryMap[dir] = berry
+			} else {
+				yarnMap[dir] = ParseYarnLock(f)
+			}
+		}
+
+		if filter.JavaScriptPnpmLock(f.Relpath()) {
+			if lock := ParsePnpmLock(f); lock != nil {
+				pnpmMap[dir] = lock
+			}
 		}
 
 		if filter.JavaScriptPackageJson(f.Relpath()) {
@@ -69,6 +86,11 @@ func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File,
 		}
 
 		if filter.JavaScriptPackageLock(f.Relpath()) {
+			// 同时存在时npm优先使用npm-shrinkwrap.json
+			shrinkwrap := filter.JavaScriptShrinkwrap(f.Relpath())
+			if shrinkwrapSet[dir] && !shrinkwrap {
+				continue
+			}
 			f.OpenReader(func(reader io.Reader) {
 				lock := readJson[PackageLock](reader)
 				if lock == nil {
@@ -76,26 +98,62 @@ func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File,
 				}
 				lockMap[dir] = lock
 			})
+			if shrinkwrap {
+				shrinkwrapSet[dir] = true
+			}
 		}
 	}
 
 	// 遍历非node_modules下的package.json
 	for dir, js := range jsonMap {
 
+		// 记录项目声明的node版本
+		call := func(file *model.File, root *model.DepGraph) {
+			root.AppendRuntime(model.Runtime_Node, js.NodeVersion())
+			call(file, root)
+		}
+
 		// 尝试从package-lock.json获取
 		if lock, ok := lockMap[dir]; ok {
-			call(js.File, ParsePackageJsonWithLock(js, lock))
+			call(js.File, withOverrides(ParsePackageJsonWithLock(js, lock), js))
+			continue
+		}
+
+		// 尝试从workspace根目录的package-lock.json获取
+		if lockdir, workspace, ok := findWorkspaceLock(lockMap, dir, func(lock *PackageLock, workspace string) bool {
+			_, ok := lock.Packages[workspace]
+			return ok && workspace != "."
+		}); ok {
+			call(js.File, withOverrides(ParsePackageJsonWithLockPackages(js, lockMap[lockdir], workspace), rootJson(jsonMap, lockdir, js)))
 			continue
 		}
 
 		// 尝试从yarn.lock获取
 		if js.File != nil {
 			if yarn, ok := yarnMap[dir]; ok {
-				call(js.File, ParsePackageJsonWithYarnLock(js, yarn))
+				call(js.File, withOverrides(ParsePackageJsonWithYarnLock(js, yarn), js))
 				continue
 			}
 		}
 
+		// 尝试从pnpm-lock.yaml获取 workspace中的lock文件位于上级目录
+		if lockdir, importer, ok := findWorkspaceLock(pnpmMap, dir, func(lock *PnpmLock, importer string) bool {
+			_, ok := lock.Importers[importer]
+			return ok
+		}); ok {
+			call(js.File, withOverrides(ParsePackageJsonWithPnpmLock(js, pnpmMap[lockdir], importer, workspaceJsons(jsonMap, lockdir)), rootJson(jsonMap, lockdir, js)))
+			continue
+		}
+
+		// 尝试从yarn berry的yarn.lock获取
+		if lockdir, workspace, ok := findWorkspaceLock(berryMap, dir, func(lock *YarnBerryLock, workspace string) bool {
+			_, ok := lock.Workspaces[workspace]
+			return ok
+		}); ok {
+			call(js.File, withOverrides(ParsePackageJsonWithYarnBerryLock(js, berryMap[lockdir], workspace, workspaceJsons(jsonMap, lockdir)), rootJson(jsonMap, lockdir, js)))
+			continue
+		}
+
 		select {
 		case <-ctx.Done():
 			return
@@ -107,6 +165,52 @@ func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File,
 	}
 }
 
+// findWorkspaceLock 查找package.json所属的workspace锁文件
+// has: 锁文件中是否存在该workspace
+// lockdir: lock文件所在目录
+// workspace: package.json所在目录相对lock文件的路径
+func findWorkspaceLock[T any](locks map[string]T, dir string, has func(lock T, workspace string) bool) (lockdir, workspace string, ok bool) {
+	for lockdir = dir; ; lockdir = path.Dir(lockdir) {
+		if lock, exist := locks[lockdir]; exist {
+			if rel, err := filepath.Rel(lockdir, dir); err == nil {
+				workspace = filepath.ToSlash(rel)
+				if ok = has(lock, workspace); ok {
+					return
+				}
+			}
+		}
+		if lockdir == path.Dir(lockdir) {
+			return
+		}
+	}
+}
+
+// withOverrides 标记锁文件依赖图中受覆盖规则影响的组件
+// pkgjson: 声明覆盖规则的package.json workspace中为根项目
+func withOverrides(root *model.DepGraph, pkgjson *PackageJson) *model.DepGraph {
+	markOverrides(root, readOverrides(pkgjson))
+	return root
+}
+
+// rootJson workspace根项目的package.json 不存在时使用当前项目
+func rootJson(jsonMap map[string]*PackageJson, lockdir string, js *PackageJson) *PackageJson {
+	if root, ok := jsonMap[lockdir]; ok {
+		return root
+	}
+	return js
+}
+
+// workspaceJsons lock文件所在目录下的package.json key:相对lock文件的路径
+func workspaceJsons(jsonMap map[string]*PackageJson, lockdir string) map[string]*PackageJson {
+	jsons := map[string]*PackageJson{}
+	for dir, js := range jsonMap {
+		if rel, err := filepath.Rel(lockdir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			jsons[filepath.ToSlash(rel)] = js
+		}
+	}
+	return jsons
+}
+
 var defaultNpmRepo = []common.RepoConfig{
 	{Url: "https://r.cnpmjs.org/"},
 }
@@ -1,10 +1,13 @@
 package javascript
 
 import (
+	"fmt"
+	"io"
 	"strings"
 
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"gopkg.in/yaml.v3"
 )
 
 type YarnLock struct {
@@ -119,3 +122,208 @@ func ParsePackageJsonWithYarnLock(pkgjson *PackageJson, yarnlock map[string]*Yar
 
 	return root
 }
+
+// YarnBerryLock yarn2+的yarn.lock
+// https://yarnpkg.com/advanced/lexicon#lockfile
+type YarnBerryLock struct {
+	// key:描述符 name@npm:^1.0.0
+	Packages map[string]*YarnBerryPackage
+	// key:workspace相对lock文件的路径
+	Workspaces map[string]*YarnBerryPackage
+}
+
+// YarnBerryPackage yarn berry锁定的组件
+type YarnBerryPackage struct {
+	// 组件名
+	Name string `yaml:"-"`
+	// 组件协议 npm/workspace/patch/portal等
+	Protocol string `yaml:"-"`
+	// 协议对应的引用 workspace路径等
+	Reference        string            `yaml:"-"`
+	Version          string            `yaml:"version"`
+	Resolution       string            `yaml:"resolution"`
+	Dependencies     map[string]string `yaml:"dependencies"`
+	DependenciesMeta map[string]struct {
+		Optional bool `yaml:"optional"`
+	} `yaml:"dependenciesMeta"`
+}
+
+// splitDescriptor 拆分描述符 @scope/name@npm:^1.0.0 => @scope/name npm:^1.0.0
+func splitDescriptor(descriptor string) (name, rng string) {
+	start := 0
+	if strings.HasPrefix(descriptor, "@") {
+		start = 1
+	}
+	i := strings.Index(descriptor[start:], "@")
+	if i == -1 {
+		return descriptor, ""
+	}
+	i += start
+	return descriptor[:i], descriptor[i+1:]
+}
+
+// ParseYarnBerryLock 解析yarn berry的yarn.lock 非berry格式返回nil
+func ParseYarnBerryLock(file *model.File) *YarnBerryLock {
+
+	/*
+		__metadata:
+		  version: 6
+
+		"cliui@npm:^6.0.0":
+		  version: 6.0.0
+		  resolution: "cliui@npm:6.0.0"
+		  dependencies:
+		    string-width: ^4.2.0
+	*/
+
+	var berry bool
+	file.ReadLine(func(line string) {
+		if strings.HasPrefix(line, "__metadata:") {
+			berry = true
+		}
+	})
+	if !berry {
+		return nil
+	}
+
+	data := map[string]*YarnBerryPackage{}
+	file.OpenReader(func(reader io.Reader) {
+		if err := yaml.NewDecoder(reader).Decode(&data); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	lock := &YarnBerryLock{
+		Packages:   map[string]*YarnBerryPackage{},
+		Workspaces: map[string]*YarnBerryPackage{},
+	}
+
+	for key, pkg := range data {
+
+		if key == "__metadata" || pkg == nil {
+			continue
+		}
+
+		// 通过resolution获取实际组件 patch:协议的组件名与原组件一致
+		var rng string
+		pkg.Name, rng = splitDescriptor(pkg.Resolution)
+		if i := strings.Index(rng, ":"); i != -1 {
+			pkg.Protocol, pkg.Reference = rng[:i], rng[i+1:]
+		}
+		if pkg.Protocol == "workspace" {
+			lock.Workspaces[pkg.Reference] = pkg
+		}
+
+		for _, descriptor := range strings.Split(key, ",") {
+			descriptor = strings.TrimSpace(descriptor)
+			lock.Packages[descriptor] = pkg
+			// portal/link等协议的描述符附带引用方 portal:../foo::locator=app%40workspace%3A.
+			if i := strings.Index(descriptor, "::"); i != -1 {
+				lock.Packages[descriptor[:i]] = pkg
+			}
+		}
+	}
+
+	return lock
+}
+
+// find 查找依赖对应的组件
+// rng: 依赖的版本范围 ^1.0.0 | workspace:* | npm:other@^1.0.0 | patch:...
+func (lock *YarnBerryLock) find(name, rng string) *YarnBerryPackage {
+	if !strings.Contains(rng, ":") {
+		rng = "npm:" + rng
+	}
+	return lock.Packages[name+"@"+rng]
+}
+
+// ParsePackageJsonWithYarnBerryLock 借助yarn berry的yarn.lock解析package.json
+// workspace: package.json所在目录相对yarn.lock的路径
+// jsons: 项目中的package.json key:相对yarn.lock的路径
+func ParsePackageJsonWithYarnBerryLock(pkgjson *PackageJson, lock *YarnBerryLock, workspace string, jsons map[string]*PackageJson) *model.DepGraph {
+
+	root := &model.DepGraph{Name: pkgjson.Name, Version: pkgjson.Version, Path: pkgjson.File.Relpath()}
+
+	g := newLockGraph()
+	visited := map[*YarnBerryPackage]bool{}
+
+	// 记录依赖对应的组件 返回组件标识
+	var resolve func(name, rng string) string
+	resolve = func(name, rng string) string {
+
+		pkg := lock.find(name, rng)
+		if pkg == nil {
+			// 锁文件中不存在的依赖
+			id := name + "@" + rng
+			g.node(id, name, rng)
+			return id
+		}
+
+		id := fmt.Sprintf("%s@%s", pkg.Name, pkg.Version)
+		var js *PackageJson
+		switch pkg.Protocol {
+		case "workspace":
+			id = "workspace:" + pkg.Reference
+			dep := g.node(id, pkg.Name, pkg.Version)
+			dep.Local = true
+			if js = jsons[pkg.Reference]; js != nil {
+				dep.Version = js.Version
+			}
+		case "portal", "link", "file":
+			// 通过本地路径引用的组件
+			id = pkg.Protocol + ":" + pkg.Reference
+			g.node(id, pkg.Name, pkg.Version).Local = true
+		default:
+			g.node(id, pkg.Name, pkg.Version)
+		}
+
+		if visited[pkg] {
+			return id
+		}
+		visited[pkg] = true
+
+		for n, r := range pkg.Dependencies {
+			// 其他workspace仅引入生产环境依赖
+			if js != nil && isDevOnly(js, n) {
+				continue
+			}
+			g.edges[id] = append(g.edges[id], lockEdge{resolve(n, r), pkg.DependenciesMeta[n].Optional})
+		}
+		return id
+	}
+
+	// 优先使用锁文件中记录的workspace依赖范围
+	deps := map[string]string{}
+	for _, m := range []map[string]string{pkgjson.DevDependencies, pkgjson.OptionalDependencies, pkgjson.Dependencies} {
+		for n, r := range m {
+			deps[n] = r
+		}
+	}
+	if ws, ok := lock.Workspaces[workspace]; ok {
+		for n, r := range ws.Dependencies {
+			deps[n] = r
+		}
+	}
+
+	var prod, dev, optional []lockEdge
+	for n, r := range deps {
+		if _, ok := pkgjson.OptionalDependencies[n]; ok {
+			optional = append(optional, lockEdge{resolve(n, r), true})
+		} else if isDevOnly(pkgjson, n) {
+			dev = append(dev, lockEdge{resolve(n, r), false})
+		} else {
+			prod = append(prod, lockEdge{resolve(n, r), false})
+		}
+	}
+
+	return g.build(root, prod, optional, dev)
+}
+
+// isDevOnly 是否仅为开发依赖
+func isDevOnly(js *PackageJson, name string) bool {
+	if _, ok := js.DevDependencies[name]; !ok {
+		return false
+	}
+	_, dep := js.Dependencies[name]
+	_, opt := js.OptionalDependencies[name]
+	return !dep && !opt
+}
@@ -0,0 +1,208 @@
+package jslib
+
+import (
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// Signature 前端组件特征 兼容retire.js的jsrepository.json
+// https://github.com/RetireJS/retire.js/blob/master/repository/jsrepository.json
+type Signature struct {
+	// npm组件名 为空时使用特征名
+	NpmName    string `json:"npmname"`
+	Extractors struct {
+		// 文件名特征
+		Filename []string `json:"filename"`
+		// 文件内容特征 如license banner
+		Filecontent []string `json:"filecontent"`
+		// 文件内容sha1 key:sha1 value:版本号
+		Hashes map[string]string `json:"hashes"`
+	} `json:"extractors"`
+}
+
+// versionPlaceholder 特征中的版本号占位符
+const versionPlaceholder = "§§version§§"
+
+// versionPattern 版本号正则
+const versionPattern = `[0-9][0-9.a-z_\-]+`
+
+// matcher 编译后的特征
+type matcher struct {
+	name        string
+	filename    []*regexp.Regexp
+	filecontent []*regexp.Regexp
+	hashes      map[string]string
+}
+
+//go:embed signatures.json
+var defaultSignatures []byte
+
+var (
+	signatures = map[string]*Signature{}
+	matchers   []*matcher
+	once       sync.Once
+	lock       sync.Mutex
+)
+
+// RegisterSignatures 注册组件特征 同名特征会覆盖内置特征
+// data: jsrepository.json格式的特征库
+func RegisterSignatures(data []byte) error {
+	sigs := map[string]*Signature{}
+	if err := json.Unmarshal(data, &sigs); err != nil {
+		return err
+	}
+	loadSignatures()
+	lock.Lock()
+	defer lock.Unlock()
+	for name, sig := range sigs {
+		signatures[name] = sig
+	}
+	matchers = compile(signatures)
+	return nil
+}
+
+// loadSignatures 加载内置特征库
+func loadSignatures() {
+	once.Do(func() {
+		lock.Lock()
+		defer lock.Unlock()
+		if err := json.Unmarshal(defaultSignatures, &signatures); err != nil {
+			logs.Warnf("load js signatures fail:%s", err)
+		}
+		matchers = compile(signatures)
+	})
+}
+
+// compile 编译特征 无法编译的正则(如retire.js中的js专有语法)将被忽略
+func compile(sigs map[string]*Signature) []*matcher {
+
+	regs := func(patterns []string, anchor bool) []*regexp.Regexp {
+		var res []*regexp.Regexp
+		for _, p := range patterns {
+			if anchor {
+				// 文件名需完整匹配 版本号使用非贪婪匹配避免包含.min等后缀
+				p = "^" + strings.ReplaceAll(p, versionPlaceholder, versionPattern+"?") + "$"
+			} else {
+				p = strings.ReplaceAll(p, versionPlaceholder, versionPattern)
+			}
+			reg, err := regexp.Compile(p)
+			if err != nil {
+				logs.Debugf("compile js signature %s fail:%s", p, err)
+				continue
+			}
+			res = append(res, reg)
+		}
+		return res
+	}
+
+	var res []*matcher
+	for name, sig := range sigs {
+		if sig == nil {
+			continue
+		}
+		if sig.NpmName != "" {
+			name = sig.NpmName
+		}
+		res = append(res, &matcher{
+			name:        name,
+			filename:    regs(sig.Extractors.Filename, true),
+			filecontent: regs(sig.Extractors.Filecontent, false),
+			hashes:      sig.Extractors.Hashes,
+		})
+	}
+	// 保证检出顺序稳定
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+	return res
+}
+
+// licenseReg banner中的许可证声明
+var licenseReg = regexp.MustCompile(`(?i)(?:@license\s+|released under the\s+|licensed under\s+(?:the\s+)?|[|-]\s*)(MIT|ISC|Apache-2\.0|BSD-[23]-Clause)\b`)
+
+// banner 匹配位置附近的文本
+func banner(content []byte, loc []int) []byte {
+	start, end := loc[0]-200, loc[1]+300
+	if start < 0 {
+		start = 0
+	}
+	if end > len(content) {
+		end = len(content)
+	}
+	return content[start:end]
+}
+
+// ParseJsFile 识别文件中嵌入的前端组件
+// 依次通过文件内容sha1、文件内容特征及文件名特征识别
+func ParseJsFile(file *model.File) *model.DepGraph {
+
+	loadSignatures()
+	lock.Lock()
+	ms := matchers
+	lock.Unlock()
+
+	var content []byte
+	file.OpenReader(func(reader io.Reader) {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			logs.Warnf("read %s fail:%s", file.Relpath(), err)
+			return
+		}
+		content = data
+	})
+
+	sum := sha1.Sum(content)
+	hash := hex.EncodeToString(sum[:])
+	filename := filepath.Base(file.Relpath())
+
+	root := &model.DepGraph{Path: file.Relpath()}
+
+	for _, m := range ms {
+
+		var version, license string
+
+		if v, ok := m.hashes[hash]; ok {
+			version = v
+		}
+
+		for _, reg := range m.filecontent {
+			if version != "" {
+				break
+			}
+			if loc := reg.FindSubmatchIndex(content); len(loc) >= 4 && loc[2] >= 0 {
+				version = string(content[loc[2]:loc[3]])
+				if lic := licenseReg.FindSubmatch(banner(content, loc)); lic != nil {
+					license = string(lic[1])
+				}
+			}
+		}
+
+		for _, reg := range m.filename {
+			if version != "" {
+				break
+			}
+			if match := reg.FindStringSubmatch(filename); len(match) >= 2 {
+				version = match[1]
+			}
+		}
+
+		if version == "" {
+			continue
+		}
+
+		dep := &model.DepGraph{Name: m.name, Version: strings.TrimRight(version, ".-_")}
+		dep.AppendLicense(license)
+		root.AppendChild(dep)
+	}
+
+	return root
+}
@@ -0,0 +1,31 @@
+package jslib
+
+import (
+	"context"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+type Sca struct{}
+
+func (sca Sca) Language() model.Language {
+	return model.Lan_JavaScript
+}
+
+func (sca Sca) Filter(relpath string) bool {
+	return filter.JavaScriptFile(relpath)
+}
+
+func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
+	for _, f := range files {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if root := ParseJsFile(f); len(root.Children) > 0 {
+			call(f, root)
+		}
+	}
+}
@@ -20,18 +20,29 @@ type ComposerJson struct {
 	License    string            `json:"license"`
 	Require    map[string]string `json:"require"`
 	RequireDev map[string]string `json:"require-dev"`
-	File       *model.File       `json:"-"`
+	// 项目自身替代或提供的组件 无需再安装
+	Replace map[string]string `json:"replace"`
+	Provide map[string]string `json:"provide"`
+	Config  struct {
+		// 指定的平台版本 如 {"php": "8.1.2"}
+		Platform map[string]string `json:"platform"`
+	} `json:"config"`
+	File *model.File `json:"-"`
 }
 
 type ComposerLock struct {
 	Packages    []*ComposerPackage `json:"packages"`
 	PackagesDev []*ComposerPackage `json:"packages-dev"`
+	// 锁定时config.platform中指定的平台版本
+	PlatformOverrides map[string]string `json:"platform-overrides"`
 }
 type ComposerPackage struct {
 	Name       string            `json:"name"`
 	Version    string            `json:"version"`
 	License    []string          `json:"license"`
 	Require    map[string]string `json:"require"`
+	Replace    map[string]string `json:"replace"`
+	Provide    map[string]string `json:"provide"`
 	requireDev map[string]string
 }
 
@@ -39,25 +50,121 @@ type ComposerRepo struct {
 	Packages map[string][]*ComposerPackage `json:"packages"`
 }
 
+// ComposerInstalled vendor/composer/installed.json
+// composer 1.x 为组件数组 composer 2.x 为 {"packages": [], "dev-package-names": []}
+type ComposerInstalled struct {
+	Packages        []*ComposerPackage `json:"packages"`
+	DevPackageNames []string           `json:"dev-package-names"`
+}
+
+// ReadComposerInstalled 读取installed.json 转换为lock结构
+func ReadComposerInstalled(reader io.Reader) *ComposerLock {
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		logs.Warn(err)
+		return nil
+	}
+
+	var installed ComposerInstalled
+	if trim := bytes.TrimSpace(data); len(trim) > 0 && trim[0] == '[' {
+		err = json.Unmarshal(trim, &installed.Packages)
+	} else {
+		err = json.Unmarshal(trim, &installed)
+	}
+	if err != nil {
+		logs.Warnf("unmarshal installed.json err: %s", err)
+		return nil
+	}
+
+	dev := map[string]bool{}
+	for _, name := range installed.DevPackageNames {
+		dev[name] = true
+	}
+
+	lock := &ComposerLock{}
+	for _, pkg := range installed.Packages {
+		if dev[pkg.Name] {
+			lock.PackagesDev = append(lock.PackagesDev, pkg)
+		} else {
+			lock.Packages = append(lock.Packages, pkg)
+		}
+	}
+	return lock
+}
+
 // skip 跳过非第三方组件
 func skip(s string) bool {
 	return !strings.Contains(s, "/")
 }
 
-func ParseComposerJsonWithLock(json *ComposerJson, lock *ComposerLock) *model.DepGraph {
+// appendPlatform 添加项目依赖的php及扩展 作为运行时组件
+// require中仅为版本约束 只添加config.platform或lock的platform-overrides中指定了版本的平台组件
+// https://getcomposer.org/doc/01-basic-usage.md#platform-packages
+func appendPlatform(root *model.DepGraph, json *ComposerJson, lock *ComposerLock) {
+	names := []string{}
+	for name := range json.Require {
+		if name == model.Runtime_Php || strings.HasPrefix(name, "ext-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		version := json.Config.Platform[name]
+		if version == "" && lock != nil {
+			version = lock.PlatformOverrides[name]
+		}
+		root.AppendRuntime(name, version)
+	}
+}
 
-	root := &model.DepGraph{Name: json.Name, Path: json.File.Relpath()}
-	root.AppendLicense(json.License)
+// providers 被其他组件替代或提供的组件 key:被替代的组件名 value:实际提供的组件名
+func providers(pkgs []*ComposerPackage) map[string]string {
+	m := map[string]string{}
+	for _, pkg := range pkgs {
+		for name := range pkg.Replace {
+			m[name] = pkg.Name
+		}
+		for name := range pkg.Provide {
+			if _, ok := m[name]; !ok {
+				m[name] = pkg.Name
+			}
+		}
+	}
+	return m
+}
+
+// ParseComposerLock 解析没有composer.json的lock
+func ParseComposerLock(file *model.File, lock *ComposerLock) *model.DepGraph {
+
+	root := &model.DepGraph{Path: file.Relpath()}
+
+	_dep := parseLockPackages(lock, nil)
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		if dep := _dep(pkg.Name); len(dep.Parents) == 0 {
+			root.AppendChild(dep)
+		}
+	}
+
+	return root
+}
+
+// parseLockPackages 记录lock中的组件及依赖关系
+// exclude: 项目自身替代或提供的组件
+// 返回根据组件名获取组件的方法 组件不存在时返回nil
+func parseLockPackages(lock *ComposerLock, exclude map[string]string) func(name string) *model.DepGraph {
 
 	_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph { return &model.DepGraph{Name: s[0]} }).LoadOrStore
 
 	// 第一次遍历记录依赖信息
+	locked := map[string]bool{}
 	for _, pkg := range lock.Packages {
 		dep := _dep(pkg.Name)
 		dep.Version = pkg.Version
 		for _, lic := range pkg.License {
 			dep.AppendLicense(lic)
 		}
+		locked[pkg.Name] = true
 	}
 	for _, pkg := range lock.PackagesDev {
 		dep := _dep(pkg.Name)
@@ -66,6 +173,21 @@ func ParseComposerJsonWithLock(json *ComposerJson, lock *ComposerLock) *model.De
 		for _, lic := range pkg.License {
 			dep.AppendLicense(lic)
 		}
+		locked[pkg.Name] = true
+	}
+
+	provided := providers(append(lock.Packages, lock.PackagesDev...))
+	find := func(name string) *model.DepGraph {
+		if locked[name] {
+			return _dep(name)
+		}
+		if _, ok := exclude[name]; ok {
+			return nil
+		}
+		if p, ok := provided[name]; ok {
+			return _dep(p)
+		}
+		return nil
 	}
 
 	// 第二次遍历记录依赖关系
@@ -75,10 +197,30 @@ func ParseComposerJsonWithLock(json *ComposerJson, lock *ComposerLock) *model.De
 			if skip(name) {
 				continue
 			}
-			dep.AppendChild(_dep(name))
+			if sub := find(name); sub != nil && sub != dep {
+				dep.AppendChild(sub)
+			}
 		}
 	}
 
+	return find
+}
+
+func ParseComposerJsonWithLock(json *ComposerJson, lock *ComposerLock) *model.DepGraph {
+
+	root := &model.DepGraph{Name: json.Name, Path: json.File.Relpath()}
+	root.AppendLicense(json.License)
+	appendPlatform(root, json, lock)
+
+	exclude := map[string]string{}
+	for name, v := range json.Replace {
+		exclude[name] = v
+	}
+	for name, v := range json.Provide {
+		exclude[name] = v
+	}
+	_dep := parseLockPackages(lock, exclude)
+
 	// 记录直接依赖
 	names := []string{}
 	for name := range json.Require {
@@ -102,9 +244,19 @@ func ParseComposerJsonWithOrigin(json *ComposerJson) *model.DepGraph {
 
 	root := &model.DepGraph{Name: json.Name, Path: json.File.Relpath()}
 	root.AppendLicense(json.License)
+	appendPlatform(root, json, nil)
 
 	_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph { return &model.DepGraph{Name: s[0], Version: s[1]} }).LoadOrStore
 
+	// 被替代或提供的组件 key:组件名 value:提供该组件的依赖 项目自身提供时为nil
+	provided := map[string]*model.DepGraph{}
+	for name := range json.Replace {
+		provided[name] = nil
+	}
+	for name := range json.Provide {
+		provided[name] = nil
+	}
+
 	parseRequire := func(n *model.DepGraph, req map[string]string, dev bool) {
 
 		names := []string{}
@@ -119,6 +271,13 @@ func ParseComposerJsonWithOrigin(json *ComposerJson) *model.DepGraph {
 				continue
 			}
 
+			if p, ok := provided[name]; ok {
+				if p != nil && p != n {
+					n.AppendChild(p)
+				}
+				continue
+			}
+
 			version := req[name]
 			subpkg := composerOrigin(name, version)
 
@@ -133,6 +292,14 @@ func ParseComposerJsonWithOrigin(json *ComposerJson) *model.DepGraph {
 			if dep.Expand == nil {
 				dep.Expand = subpkg
 				dep.Develop = dev
+				for name := range subpkg.Replace {
+					provided[name] = dep
+				}
+				for name := range subpkg.Provide {
+					if _, ok := provided[name]; !ok {
+						provided[name] = dep
+					}
+				}
 			} else if !dev {
 				dep.Develop = dev
 			}
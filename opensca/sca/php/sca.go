@@ -19,13 +19,18 @@ func (sca Sca) Language() model.Language {
 }
 
 func (sca Sca) Filter(relpath string) bool {
-	return filter.PhpComposer(relpath) || filter.PhpComposerLock(relpath)
+	return filter.PhpComposer(relpath) ||
+		filter.PhpComposerLock(relpath) ||
+		filter.PhpComposerInstalled(relpath)
 }
 
 func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
 
 	jsonMap := map[string]*ComposerJson{}
 	lockMap := map[string]*ComposerLock{}
+	// vendor/composer/installed.json key:项目目录
+	installedMap := map[string]*ComposerLock{}
+	installedFile := map[string]*model.File{}
 
 	path2dir := func(relpath string) string { return path.Dir(strings.ReplaceAll(relpath, `\`, `/`)) }
 
@@ -43,6 +48,14 @@ func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File,
 				json.NewDecoder(reader).Decode(&lock)
 				lockMap[path2dir(f.Relpath())] = &lock
 			})
+		} else if filter.PhpComposerInstalled(f.Relpath()) {
+			f.OpenReader(func(reader io.Reader) {
+				if lock := ReadComposerInstalled(reader); lock != nil {
+					dir := path.Dir(path.Dir(path2dir(f.Relpath())))
+					installedMap[dir] = lock
+					installedFile[dir] = f
+				}
+			})
 		}
 	}
 
@@ -52,6 +65,14 @@ loop:
 		// 通过lock文件补全
 		if lock, ok := lockMap[dir]; ok {
 			call(json.File, ParseComposerJsonWithLock(json, lock))
+			delete(installedMap, dir)
+			continue
+		}
+
+		// 没有lock时使用已安装的组件
+		if lock, ok := installedMap[dir]; ok {
+			call(json.File, ParseComposerJsonWithLock(json, lock))
+			delete(installedMap, dir)
 			continue
 		}
 
@@ -71,6 +92,11 @@ loop:
 		// 从数据源下载
 		call(json.File, ParseComposerJsonWithOrigin(json))
 	}
+
+	// 仅有installed.json的项目 如部署后的应用
+	for dir, lock := range installedMap {
+		call(installedFile[dir], ParseComposerLock(installedFile[dir], lock))
+	}
 }
 
 var defaultComposerRepo = []common.RepoConfig{
@@ -0,0 +1,222 @@
+package python
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+// PyDist 已安装的python组件 *.dist-info/METADATA *.egg-info/PKG-INFO
+// https://packaging.python.org/en/latest/specifications/core-metadata/
+type PyDist struct {
+	Name    string
+	Version string
+	License string
+	// Requires-Dist PEP 508格式的依赖声明 包含环境标记及extra
+	Requires []string
+	File     *model.File
+}
+
+// readMetadata 读取core metadata中的字段 遇到空行后为描述信息
+func readMetadata(reader io.Reader) map[string][]string {
+	headers := map[string][]string{}
+	var key string
+	end := false
+	model.ReadLine(reader, func(line string) {
+		if end {
+			return
+		}
+		if strings.TrimSpace(line) == "" {
+			end = true
+			return
+		}
+		// 多行字段
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && key != "" {
+			values := headers[key]
+			values[len(values)-1] += "\n" + strings.TrimSpace(line)
+			return
+		}
+		i := strings.Index(line, ":")
+		if i == -1 {
+			return
+		}
+		key = strings.ToLower(strings.TrimSpace(line[:i]))
+		headers[key] = append(headers[key], strings.TrimSpace(line[i+1:]))
+	})
+	return headers
+}
+
+// ReadPyDist 读取已安装组件的元数据
+// requires: egg-info中的requires.txt 不存在时为nil
+func ReadPyDist(metadata, requires *model.File) *PyDist {
+
+	var dist *PyDist
+	metadata.OpenReader(func(reader io.Reader) {
+		dist = newPyDist(readMetadata(reader))
+	})
+	if dist == nil {
+		return nil
+	}
+	dist.File = metadata
+
+	// egg-info的依赖记录在requires.txt 分节([extra]或[:marker]或[extra:marker])为条件依赖
+	if requires != nil {
+		var marker string
+		requires.ReadLine(func(line string) {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				return
+			}
+			if strings.HasPrefix(line, "[") {
+				extra, cond, _ := strings.Cut(strings.Trim(line, "[]"), ":")
+				var markers []string
+				if extra = strings.TrimSpace(extra); extra != "" {
+					markers = append(markers, fmt.Sprintf(`extra == "%s"`, extra))
+				}
+				if cond = strings.TrimSpace(cond); cond != "" {
+					markers = append(markers, fmt.Sprintf("(%s)", cond))
+				}
+				marker = strings.Join(markers, " and ")
+				return
+			}
+			if marker != "" {
+				line = fmt.Sprintf("%s; %s", line, marker)
+			}
+			dist.Requires = append(dist.Requires, line)
+		})
+	}
+
+	return dist
+}
+
+// newPyDist 通过core metadata字段创建组件
+func newPyDist(headers map[string][]string) *PyDist {
+
+	first := func(key string) string {
+		if values := headers[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	dist := &PyDist{
+		Name:    first("name"),
+		Version: first("version"),
+	}
+	if dist.Name == "" {
+		return nil
+	}
+
+	// 许可证 优先使用License-Expression 其次为单行的License 最后为分类信息
+	if lic := first("license-expression"); lic != "" {
+		dist.License = lic
+	} else if lic := first("license"); lic != "" && lic != "UNKNOWN" && !strings.Contains(lic, "\n") {
+		dist.License = lic
+	} else {
+		for _, classifier := range headers["classifier"] {
+			if strings.HasPrefix(classifier, "License ::") {
+				s := strings.Split(classifier, "::")
+				dist.License = strings.TrimSpace(s[len(s)-1])
+			}
+		}
+	}
+
+	dist.Requires = append(dist.Requires, headers["requires-dist"]...)
+
+	return dist
+}
+
+// distDir 组件元数据所在的dist-info/egg-info目录 legacy的egg-info文件为其本身
+func distDir(relpath string) string {
+	relpath = strings.ReplaceAll(relpath, `\`, `/`)
+	if strings.HasSuffix(relpath, ".egg-info") {
+		return relpath
+	}
+	return path.Dir(relpath)
+}
+
+// ParsePyEnv 解析python环境(site-packages)中已安装的组件
+// 未被其他组件依赖的组件作为直接依赖
+func ParsePyEnv(dir string, dists []*PyDist) *model.DepGraph {
+
+	sort.Slice(dists, func(i, j int) bool { return normalizeName(dists[i].Name) < normalizeName(dists[j].Name) })
+
+	g := newPyLockGraph()
+	for _, dist := range dists {
+		g.node(dist.Name, dist.Version).AppendLicense(dist.License)
+	}
+	for _, dist := range dists {
+		dep := g.node(dist.Name, dist.Version)
+		for _, spec := range dist.Requires {
+			// 仅在启用extra或满足环境标记时引入的依赖
+			req := parseRequirement(spec)
+			if req == nil || !pyEnv.match(req.Marker, nil) {
+				continue
+			}
+			// 未安装的依赖忽略
+			if sub := g.find(req.Name, ""); sub != nil && sub != dep {
+				dep.AppendChild(sub)
+			}
+		}
+	}
+
+	var requires []pyRequire
+	for _, dep := range g.roots() {
+		requires = append(requires, pyRequire{Name: dep.Name, Version: dep.Version})
+	}
+
+	return g.build(&model.DepGraph{Path: dir}, requires)
+}
+
+// ParsePyPackage 解析wheel/egg中的组件 子依赖为声明的版本约束
+func ParsePyPackage(dist *PyDist) *model.DepGraph {
+	root := &model.DepGraph{Name: dist.Name, Version: dist.Version, Path: path.Dir(distDir(dist.File.Relpath()))}
+	root.AppendLicense(dist.License)
+	for _, spec := range dist.Requires {
+		if req := parseRequirement(spec); req != nil && pyEnv.match(req.Marker, nil) {
+			root.AppendChild(&model.DepGraph{Name: req.Name, Version: req.Version})
+		}
+	}
+	return root
+}
+
+// parsePyDists 解析已安装组件及wheel/egg包
+func parsePyDists(files []*model.File, call model.ResCallback) {
+
+	// 组件元数据 key:dist-info/egg-info目录
+	metadatas := map[string]*model.File{}
+	requires := map[string]*model.File{}
+	for _, file := range files {
+		if filter.PythonDistRequires(file.Relpath()) {
+			requires[distDir(file.Relpath())] = file
+		} else if filter.PythonDistMetadata(file.Relpath()) {
+			metadatas[distDir(file.Relpath())] = file
+		}
+	}
+
+	// 同一目录下的组件属于同一环境 key:site-packages目录
+	envs := map[string][]*PyDist{}
+	for _, dir := range sortedKeys(metadatas) {
+		if dist := ReadPyDist(metadatas[dir], requires[dir]); dist != nil {
+			env := path.Dir(dir)
+			envs[env] = append(envs[env], dist)
+		}
+	}
+
+	for _, env := range sortedKeys(envs) {
+		dists := envs[env]
+		// wheel/egg包作为单独的组件
+		if filter.PythonWheel(env) {
+			for _, dist := range dists {
+				call(dist.File, ParsePyPackage(dist))
+			}
+			continue
+		}
+		call(dists[0].File, ParsePyEnv(env, dists))
+	}
+}
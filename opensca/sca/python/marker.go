@@ -0,0 +1,256 @@
+package python
+
+import (
+	"strings"
+)
+
+// PyEnv 目标环境 用于计算依赖声明中的环境标记(marker)
+// https://packaging.python.org/en/latest/specifications/dependency-specifiers/#environment-markers
+type PyEnv struct {
+	// python版本 如3.12
+	PythonVersion string
+	// 平台 linux|win32|darwin
+	SysPlatform string
+	// 架构 如x86_64|AMD64|arm64
+	PlatformMachine string
+	// 项目启用的extras
+	Extras []string
+}
+
+var pyEnv = PyEnv{
+	PythonVersion:   "3.12",
+	SysPlatform:     "linux",
+	PlatformMachine: "x86_64",
+}
+
+// RegisterPyEnv 注册目标环境 未设置的字段使用默认值
+func RegisterPyEnv(env PyEnv) {
+	if env.PythonVersion != "" {
+		pyEnv.PythonVersion = env.PythonVersion
+	}
+	if env.SysPlatform != "" {
+		pyEnv.SysPlatform = env.SysPlatform
+	}
+	if env.PlatformMachine != "" {
+		pyEnv.PlatformMachine = env.PlatformMachine
+	}
+	if len(env.Extras) > 0 {
+		pyEnv.Extras = env.Extras
+	}
+}
+
+// vars 环境标记变量
+func (env PyEnv) vars() map[string]string {
+
+	vars := map[string]string{
+		"python_version":                 env.PythonVersion,
+		"python_full_version":            env.PythonVersion,
+		"sys_platform":                   env.SysPlatform,
+		"platform_machine":               env.PlatformMachine,
+		"os_name":                        "posix",
+		"platform_system":                "Linux",
+		"platform_python_implementation": "CPython",
+		"implementation_name":            "cpython",
+		"implementation_version":         env.PythonVersion,
+		"platform_release":               "",
+		"platform_version":               "",
+	}
+
+	// python_version只包含主次版本号
+	if s := strings.Split(env.PythonVersion, "."); len(s) > 2 {
+		vars["python_version"] = strings.Join(s[:2], ".")
+	}
+
+	switch {
+	case strings.HasPrefix(env.SysPlatform, "win"):
+		vars["os_name"] = "nt"
+		vars["platform_system"] = "Windows"
+	case env.SysPlatform == "darwin":
+		vars["platform_system"] = "Darwin"
+	case env.SysPlatform != "linux":
+		vars["platform_system"] = env.SysPlatform
+	}
+
+	return vars
+}
+
+// match 依赖声明的环境标记是否满足
+// extras: 启用的extras 标记中包含extra时满足任一extra即可
+func (env PyEnv) match(marker string, extras []string) bool {
+
+	marker = strings.TrimSpace(marker)
+	if marker == "" {
+		return true
+	}
+
+	vars := env.vars()
+	if !strings.Contains(marker, "extra") || len(extras) == 0 {
+		vars["extra"] = ""
+		return evalMarker(marker, vars)
+	}
+	for _, extra := range extras {
+		vars["extra"] = extra
+		if evalMarker(marker, vars) {
+			return true
+		}
+	}
+	return false
+}
+
+// evalMarker 计算环境标记 无法解析时视为满足
+func evalMarker(marker string, vars map[string]string) bool {
+	p := &markerParser{tokens: markerTokens(marker), vars: vars}
+	ok := p.or()
+	if p.err || p.i != len(p.tokens) {
+		return true
+	}
+	return ok
+}
+
+// markerTokens 环境标记分词
+func markerTokens(marker string) []string {
+	var tokens []string
+	for i := 0; i < len(marker); {
+		c := marker[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"' || c == '\'':
+			j := strings.IndexByte(marker[i+1:], c)
+			if j == -1 {
+				tokens = append(tokens, marker[i:])
+				i = len(marker)
+				break
+			}
+			tokens = append(tokens, marker[i:i+j+2])
+			i += j + 2
+		case strings.ContainsRune("<>=!~", rune(c)):
+			j := i
+			for j < len(marker) && strings.ContainsRune("<>=!~", rune(marker[j])) {
+				j++
+			}
+			tokens = append(tokens, marker[i:j])
+			i = j
+		default:
+			j := i
+			for j < len(marker) && !strings.ContainsRune(" \t()'\"<>=!~", rune(marker[j])) {
+				j++
+			}
+			tokens = append(tokens, marker[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+// markerParser 环境标记解析
+// marker_or: marker_and ('or' marker_and)*
+// marker_and: marker_expr ('and' marker_expr)*
+// marker_expr: '(' marker_or ')' | value op value
+type markerParser struct {
+	tokens []string
+	i      int
+	vars   map[string]string
+	err    bool
+}
+
+func (p *markerParser) peek() string {
+	if p.i < len(p.tokens) {
+		return p.tokens[p.i]
+	}
+	return ""
+}
+
+func (p *markerParser) next() string {
+	t := p.peek()
+	if p.i < len(p.tokens) {
+		p.i++
+	} else {
+		p.err = true
+	}
+	return t
+}
+
+func (p *markerParser) or() bool {
+	ok := p.and()
+	for p.peek() == "or" {
+		p.next()
+		// 需要完整解析后续表达式
+		right := p.and()
+		ok = ok || right
+	}
+	return ok
+}
+
+func (p *markerParser) and() bool {
+	ok := p.expr()
+	for p.peek() == "and" {
+		p.next()
+		right := p.expr()
+		ok = ok && right
+	}
+	return ok
+}
+
+func (p *markerParser) expr() bool {
+
+	if p.peek() == "(" {
+		p.next()
+		ok := p.or()
+		if p.next() != ")" {
+			p.err = true
+		}
+		return ok
+	}
+
+	left, lvar := p.value()
+	op := p.next()
+	if op == "not" {
+		if p.next() != "in" {
+			p.err = true
+		}
+		op = "not in"
+	}
+	right, rvar := p.value()
+
+	// extra比较时需规范化
+	if lvar == "extra" || rvar == "extra" {
+		left, right = normalizeName(left), normalizeName(right)
+	}
+
+	switch op {
+	case "in":
+		return strings.Contains(right, left)
+	case "not in":
+		return !strings.Contains(right, left)
+	}
+
+	// 均为版本号时按版本比较
+	if lv, rv := parsePyVersion(left), parsePyVersion(strings.TrimSuffix(right, ".*")); lv != nil && rv != nil && op != "===" {
+		return pyClause{op: op, version: right}.match(left, lv)
+	}
+
+	switch op {
+	case "==", "===":
+		return left == right
+	case "!=":
+		return left != right
+	}
+	return false
+}
+
+// value 标记中的值 返回值及变量名
+func (p *markerParser) value() (string, string) {
+	t := p.next()
+	if len(t) >= 2 && (t[0] == '"' || t[0] == '\'') && t[len(t)-1] == t[0] {
+		return t[1 : len(t)-1], ""
+	}
+	v, ok := p.vars[t]
+	if !ok {
+		p.err = true
+	}
+	return v, t
+}
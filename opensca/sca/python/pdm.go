@@ -0,0 +1,77 @@
+package python
+
+import (
+	"io"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PdmLock pdm.lock
+type PdmLock struct {
+	Packages []struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+		// 启用extra的组件会单独记录一条
+		Extras []string `toml:"extras"`
+		// default为生产依赖 其他为开发依赖或可选依赖
+		Groups []string `toml:"groups"`
+		// PEP 508格式的依赖声明
+		Dependencies []string `toml:"dependencies"`
+	} `toml:"package"`
+}
+
+// ParsePdmLock 解析pdm.lock
+// pyproject: 同目录下的pyproject.toml 不存在时为nil
+func ParsePdmLock(file *model.File, pyproject *PyProject) *model.DepGraph {
+
+	lock := &PdmLock{}
+	file.OpenReader(func(reader io.Reader) {
+		if _, err := toml.NewDecoder(reader).Decode(lock); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	g := newPyLockGraph()
+	for _, pkg := range lock.Packages {
+		g.node(pkg.Name, pkg.Version)
+	}
+	for _, pkg := range lock.Packages {
+		dep := g.node(pkg.Name, pkg.Version)
+		for _, spec := range pkg.Dependencies {
+			name, _ := splitRequirement(spec)
+			// extra组件依赖自身
+			if normalizeName(name) == normalizeName(pkg.Name) {
+				continue
+			}
+			if sub := g.find(name, ""); sub != nil {
+				dep.AppendChild(sub)
+			}
+		}
+	}
+
+	root := &model.DepGraph{Path: file.Relpath()}
+	if pyproject != nil {
+		root.Name, root.Version = pyproject.Name(), pyproject.Version()
+		return g.build(root, pyproject.Requires())
+	}
+
+	// 没有pyproject.toml时使用锁文件中的分组信息
+	develop := map[string]bool{}
+	for _, pkg := range lock.Packages {
+		dev := len(pkg.Groups) > 0
+		for _, group := range pkg.Groups {
+			if group == "default" {
+				dev = false
+			}
+		}
+		develop[normalizeName(pkg.Name)] = dev
+	}
+	var requires []pyRequire
+	for _, dep := range g.roots() {
+		requires = append(requires, pyRequire{Name: dep.Name, Version: dep.Version, Develop: develop[normalizeName(dep.Name)]})
+	}
+	return g.build(root, requires)
+}
@@ -0,0 +1,288 @@
+package python
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pyVersion PEP 440版本号
+// https://packaging.python.org/en/latest/specifications/version-specifiers/
+type pyVersion struct {
+	epoch   int
+	release []int
+	// 预发布版本 a=0 b=1 rc=2 -1为非预发布版本
+	preKind int
+	preNum  int
+	// -1为非post版本
+	post int
+	// -1为非dev版本
+	dev   int
+	local string
+}
+
+var pyVersionReg = regexp.MustCompile(`(?i)^v?(?:(\d+)!)?(\d+(?:\.\d+)*)(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d+)?)?(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?(?:[-_.]?(dev)[-_.]?(\d+)?)?(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// parsePyVersion 解析版本号 不符合PEP 440时返回nil
+func parsePyVersion(s string) *pyVersion {
+
+	m := pyVersionReg.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil
+	}
+
+	atoi := func(s string) int {
+		i, _ := strconv.Atoi(s)
+		return i
+	}
+
+	v := &pyVersion{epoch: atoi(m[1]), preKind: -1, post: -1, dev: -1, local: strings.ToLower(m[10])}
+	for _, r := range strings.Split(m[2], ".") {
+		v.release = append(v.release, atoi(r))
+	}
+	switch strings.ToLower(m[3]) {
+	case "a", "alpha":
+		v.preKind = 0
+	case "b", "beta":
+		v.preKind = 1
+	case "c", "rc", "pre", "preview":
+		v.preKind = 2
+	}
+	v.preNum = atoi(m[4])
+	if m[5] != "" {
+		v.post = atoi(m[5])
+	} else if m[6] != "" {
+		v.post = atoi(m[7])
+	}
+	if m[8] != "" {
+		v.dev = atoi(m[9])
+	}
+	return v
+}
+
+// isPre 是否为预发布版本(包含dev版本)
+func (v *pyVersion) isPre() bool {
+	return v.preKind != -1 || v.dev != -1
+}
+
+// key 用于比较的版本序列
+func (v *pyVersion) key() []int {
+
+	release := v.release
+	for len(release) > 1 && release[len(release)-1] == 0 {
+		release = release[:len(release)-1]
+	}
+
+	key := []int{v.epoch}
+	key = append(key, release...)
+	// 补齐长度避免release与后续字段错位
+	for i := len(release); i < 16; i++ {
+		key = append(key, 0)
+	}
+
+	// 1.0.dev0 < 1.0a0 < 1.0 < 1.0.post0
+	switch {
+	case v.preKind == -1 && v.post == -1 && v.dev != -1:
+		key = append(key, math.MinInt, 0)
+	case v.preKind == -1:
+		key = append(key, math.MaxInt, 0)
+	default:
+		key = append(key, v.preKind, v.preNum)
+	}
+	if v.post == -1 {
+		key = append(key, math.MinInt)
+	} else {
+		key = append(key, v.post)
+	}
+	if v.dev == -1 {
+		key = append(key, math.MaxInt)
+	} else {
+		key = append(key, v.dev)
+	}
+	return key
+}
+
+// compare 比较版本 不比较local
+func (v *pyVersion) compare(o *pyVersion) int {
+	a, b := v.key(), o.key()
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// pyClause 单个版本约束 如 >=1.0
+type pyClause struct {
+	op      string
+	version string
+}
+
+var pyOps = []string{"===", "~=", "==", "!=", "<=", ">=", "<", ">"}
+
+// pySpecifier 版本约束 如 >=1.0,<2.0
+type pySpecifier []pyClause
+
+// parsePySpecifier 解析版本约束 无效的约束返回false
+func parsePySpecifier(spec string) (pySpecifier, bool) {
+
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "*" {
+		return nil, true
+	}
+
+	var s pySpecifier
+	for _, clause := range strings.Split(spec, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		op := ""
+		for _, o := range pyOps {
+			if strings.HasPrefix(clause, o) {
+				op = o
+				break
+			}
+		}
+		// 不带操作符的版本号视为==
+		if op == "" {
+			op, clause = "==", "=="+clause
+		}
+		version := strings.TrimSpace(clause[len(op):])
+		if op != "===" && parsePyVersion(strings.TrimSuffix(version, ".*")) == nil {
+			return nil, false
+		}
+		s = append(s, pyClause{op: op, version: version})
+	}
+	return s, true
+}
+
+// prerelease 约束中是否显式包含预发布版本
+func (s pySpecifier) prerelease() bool {
+	for _, c := range s {
+		if c.op == "!=" {
+			continue
+		}
+		if v := parsePyVersion(strings.TrimSuffix(c.version, ".*")); v != nil && v.isPre() {
+			return true
+		}
+	}
+	return false
+}
+
+// match 版本是否满足约束
+func (s pySpecifier) match(version string) bool {
+	v := parsePyVersion(version)
+	for _, c := range s {
+		if !c.match(version, v) {
+			return false
+		}
+	}
+	return v != nil || len(s) == 0
+}
+
+func (c pyClause) match(raw string, v *pyVersion) bool {
+
+	if c.op == "===" {
+		return strings.EqualFold(strings.TrimSpace(raw), c.version)
+	}
+	if v == nil {
+		return false
+	}
+
+	// 前缀匹配 ==1.1.*
+	if strings.HasSuffix(c.version, ".*") {
+		prefix := parsePyVersion(strings.TrimSuffix(c.version, ".*"))
+		ok := v.epoch == prefix.epoch
+		for i, r := range prefix.release {
+			n := 0
+			if i < len(v.release) {
+				n = v.release[i]
+			}
+			ok = ok && n == r
+		}
+		if c.op == "!=" {
+			return !ok
+		}
+		return ok
+	}
+
+	cv := parsePyVersion(c.version)
+	cmp := v.compare(cv)
+
+	switch c.op {
+	case "==", "!=":
+		// 约束中没有local时忽略候选版本的local
+		eq := cmp == 0 && (cv.local == "" || cv.local == v.local)
+		return eq == (c.op == "==")
+	case "~=":
+		// ~=2.2.1 等价于 >=2.2.1,==2.2.*
+		if cmp < 0 || len(cv.release) < 2 {
+			return false
+		}
+		for i, r := range cv.release[:len(cv.release)-1] {
+			if i >= len(v.release) || v.release[i] != r {
+				return false
+			}
+		}
+		return v.epoch == cv.epoch
+	case "<=":
+		return cmp <= 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		// <V 不包含V的预发布版本
+		if cmp >= 0 {
+			return false
+		}
+		return cv.isPre() || !v.isPre() || !sameRelease(v, cv)
+	case ">":
+		// >V 不包含V的post版本
+		if cmp <= 0 {
+			return false
+		}
+		return cv.post != -1 || v.post == -1 || !sameRelease(v, cv)
+	}
+	return false
+}
+
+// sameRelease 是否为同一发布版本
+func sameRelease(a, b *pyVersion) bool {
+	x := &pyVersion{epoch: a.epoch, release: a.release, preKind: -1, post: -1, dev: -1}
+	y := &pyVersion{epoch: b.epoch, release: b.release, preKind: -1, post: -1, dev: -1}
+	return x.compare(y) == 0
+}
+
+// maxPyVersion 获取满足约束的最高版本 仅在没有满足约束的正式版本时使用预发布版本
+func maxPyVersion(versions []string, spec string) string {
+
+	s, ok := parsePySpecifier(spec)
+	if !ok {
+		return ""
+	}
+
+	pick := func(pre bool) string {
+		var max string
+		var maxv *pyVersion
+		for _, version := range versions {
+			v := parsePyVersion(version)
+			if v == nil || (v.isPre() && !pre) || !s.match(version) {
+				continue
+			}
+			if maxv == nil || v.compare(maxv) > 0 {
+				max, maxv = version, v
+			}
+		}
+		return max
+	}
+
+	if version := pick(s.prerelease()); version != "" {
+		return version
+	}
+	return pick(true)
+}
@@ -2,43 +2,93 @@ package python
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"strings"
 
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+
+	"github.com/BurntSushi/toml"
 )
 
+// ParsePipfile 解析Pipfile中声明的直接依赖
 func ParsePipfile(file *model.File) *model.DepGraph {
 
+	// value: "*" | "==1.0" | {version = "==1.0", extras = [...]}
 	pip := struct {
-		DevPackages map[string]string `toml:"dev-packages"`
-		Packages    map[string]string `toml:"packages"`
+		DevPackages map[string]any `toml:"dev-packages"`
+		Packages    map[string]any `toml:"packages"`
 	}{}
 
 	root := &model.DepGraph{Path: file.Relpath()}
 
-	_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph { return &model.DepGraph{Name: s[0], Version: s[1]} }).LoadOrStore
-
 	file.OpenReader(func(reader io.Reader) {
-		if err := json.NewDecoder(reader).Decode(&pip); err != nil {
+		if _, err := toml.NewDecoder(reader).Decode(&pip); err != nil {
 			logs.Warnf("unmarshal file %s err: %s", file.Relpath(), err)
 		}
 	})
 
-	for name, version := range pip.Packages {
-		root.AppendChild(_dep(name, version))
+	add := func(packages map[string]any, dev bool) {
+		for _, name := range sortedKeys(packages) {
+			req := pipfileRequirement(name, packages[name])
+			if !pyEnv.match(req.Marker, pyEnv.Extras) {
+				continue
+			}
+			root.AppendChild(&model.DepGraph{Name: name, Version: req.Version, Develop: dev, Expand: req.Extras})
+		}
+	}
+	add(pip.Packages, false)
+	add(pip.DevPackages, true)
+
+	return root
+}
+
+// pipfileRequirement Pipfile中的依赖声明
+// value: "*" | "==1.0" | {version = "==1.0", extras = [...], markers = "...", sys_platform = "== 'win32'"}
+func pipfileRequirement(name string, value any) *pyRequirement {
+
+	req := &pyRequirement{Name: name}
+	version, _ := poetrySpec(value)
+
+	// *代表任意版本
+	if version == "*" {
+		version = ""
 	}
-	for name, version := range pip.DevPackages {
-		dep := _dep(name, version)
-		if dep == nil {
+	if strings.HasPrefix(version, "==") && !strings.Contains(version, ",") {
+		version = strings.TrimPrefix(version, "==")
+	}
+	req.Version = version
+
+	spec, ok := value.(map[string]any)
+	if !ok {
+		return req
+	}
+	if extras, ok := spec["extras"].([]any); ok {
+		for _, extra := range extras {
+			if e, ok := extra.(string); ok {
+				req.Extras = append(req.Extras, e)
+			}
+		}
+	}
+
+	var markers []string
+	if marker, ok := spec["markers"].(string); ok && marker != "" {
+		markers = append(markers, fmt.Sprintf("(%s)", marker))
+	}
+	// 以环境标记变量名为key的条件
+	vars := pyEnv.vars()
+	for _, key := range sortedKeys(spec) {
+		if _, ok := vars[key]; !ok {
 			continue
 		}
-		dep.Develop = true
-		root.AppendChild(dep)
+		if cond, ok := spec[key].(string); ok && cond != "" {
+			markers = append(markers, fmt.Sprintf("%s %s", key, cond))
+		}
 	}
+	req.Marker = strings.Join(markers, " and ")
 
-	return root
+	return req
 }
 
 func ParsePipfileLock(file *model.File) *model.DepGraph {
@@ -67,29 +117,25 @@ func ParsePipfileLock(file *model.File) *model.DepGraph {
 	return root
 }
 
+// ParseRequirementTxt 解析requirements.txt中声明的直接依赖
 func ParseRequirementTxt(file *model.File) *model.DepGraph {
 
 	root := &model.DepGraph{Path: file.Relpath()}
 
 	file.ReadLine(func(line string) {
 
-		if strings.HasPrefix(line, `-r`) {
-			return
-		}
-
 		if i := strings.Index(line, "#"); i != -1 {
 			line = line[:i]
 		}
 
-		line = strings.TrimSpace(strings.Split(line, ";")[0])
-		if strings.ContainsAny(line, `#$%`) || len(line) == 0 {
+		// -r/-i/-e等选项
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "-") || strings.ContainsAny(line, `$%`) || len(line) == 0 {
 			return
 		}
 
-		if i := strings.IndexAny(line, "=!<>~"); i == -1 {
-			root.AppendChild(&model.DepGraph{Name: line})
-		} else {
-			root.AppendChild(&model.DepGraph{Name: line[:i], Version: strings.TrimPrefix(line[i:], "==")})
+		if req := parseRequirement(line); req != nil && pyEnv.match(req.Marker, pyEnv.Extras) {
+			root.AppendChild(&model.DepGraph{Name: req.Name, Version: req.Version, Expand: req.Extras})
 		}
 
 	})
@@ -107,12 +153,8 @@ func ParseRequirementIn(file *model.File) *model.DepGraph {
 			return
 		}
 
-		words := strings.Fields(line)
-		if len(words) > 0 {
-			dep := &model.DepGraph{}
-			dep.Name = words[0]
-			dep.Version = strings.Join(words[1:], "")
-			root.AppendChild(dep)
+		if req := parseRequirement(line); req != nil && pyEnv.match(req.Marker, pyEnv.Extras) {
+			root.AppendChild(&model.DepGraph{Name: req.Name, Version: req.Version, Expand: req.Extras})
 		}
 
 	})
@@ -0,0 +1,87 @@
+package python
+
+import (
+	"io"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PoetryLock poetry.lock
+type PoetryLock struct {
+	Packages []struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+		// 旧版本中的分组 main|dev
+		Category string `toml:"category"`
+		// poetry 2.0 中的分组
+		Groups   []string `toml:"groups"`
+		Optional bool     `toml:"optional"`
+		// value: ">=1.0" | {version = ">=1.0", optional = true} | [{...}]
+		Dependencies map[string]any `toml:"dependencies"`
+	} `toml:"package"`
+}
+
+// ParsePoetryLock 解析poetry.lock
+// pyproject: 同目录下的pyproject.toml 不存在时为nil
+func ParsePoetryLock(file *model.File, pyproject *PyProject) *model.DepGraph {
+
+	lock := &PoetryLock{}
+	file.OpenReader(func(reader io.Reader) {
+		if _, err := toml.NewDecoder(reader).Decode(lock); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	g := newPyLockGraph()
+	for _, pkg := range lock.Packages {
+		g.node(pkg.Name, pkg.Version)
+	}
+	for _, pkg := range lock.Packages {
+		dep := g.node(pkg.Name, pkg.Version)
+		for _, name := range sortedKeys(pkg.Dependencies) {
+			// 可选依赖仅在启用extra时引入
+			if _, optional := poetrySpec(pkg.Dependencies[name]); optional {
+				continue
+			}
+			if sub := g.find(name, ""); sub != nil {
+				dep.AppendChild(sub)
+			}
+		}
+	}
+
+	root := &model.DepGraph{Path: file.Relpath()}
+	if pyproject != nil {
+		root.Name, root.Version = pyproject.Name(), pyproject.Version()
+		return g.build(root, pyproject.Requires())
+	}
+
+	// 没有pyproject.toml时使用锁文件中的分组信息
+	develop := map[string]bool{}
+	optional := map[string]bool{}
+	for _, pkg := range lock.Packages {
+		dev := pkg.Category == "dev"
+		if len(pkg.Groups) > 0 {
+			dev = true
+			for _, group := range pkg.Groups {
+				if group == "main" {
+					dev = false
+				}
+			}
+		}
+		develop[normalizeName(pkg.Name)] = dev
+		optional[normalizeName(pkg.Name)] = pkg.Optional
+	}
+	var requires []pyRequire
+	for _, dep := range g.roots() {
+		requires = append(requires, pyRequire{
+			Name:     dep.Name,
+			Version:  dep.Version,
+			Develop:  develop[normalizeName(dep.Name)],
+			Optional: optional[normalizeName(dep.Name)],
+		})
+	}
+	return g.build(root, requires)
+}
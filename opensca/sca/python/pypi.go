@@ -0,0 +1,410 @@
+package python
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/common"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/cache"
+)
+
+// pypiJson pypi json api中的组件信息
+// https://docs.pypi.org/api/json/
+type pypiJson struct {
+	Info struct {
+		Name              string   `json:"name"`
+		Version           string   `json:"version"`
+		License           string   `json:"license"`
+		LicenseExpression string   `json:"license_expression"`
+		Classifiers       []string `json:"classifiers"`
+		RequiresDist      []string `json:"requires_dist"`
+	} `json:"info"`
+	Releases map[string][]pypiFile `json:"releases"`
+}
+
+// pypiFile 版本的发布文件
+type pypiFile struct {
+	Yanked bool `json:"yanked"`
+}
+
+// versions 可用的版本 不包含已撤回的版本
+func (p *pypiJson) versions() []string {
+	var versions []string
+	for version, files := range p.Releases {
+		for _, f := range files {
+			if !f.Yanked {
+				versions = append(versions, version)
+				break
+			}
+		}
+	}
+	return versions
+}
+
+// dist 组件信息
+func (p *pypiJson) dist() *PyDist {
+	info := p.Info
+	return newPyDist(map[string][]string{
+		"name":               {info.Name},
+		"version":            {info.Version},
+		"license":            {info.License},
+		"license-expression": {info.LicenseExpression},
+		"classifier":         info.Classifiers,
+		"requires-dist":      info.RequiresDist,
+	})
+}
+
+var defaultPypiRepo = []common.RepoConfig{
+	{Url: "https://pypi.org/pypi"},
+}
+
+// RegisterPypiRepo 注册pypi仓库 支持json api及simple api(以/simple结尾)
+func RegisterPypiRepo(repos ...common.RepoConfig) {
+	newRepo := common.TrimRepo(repos...)
+	if len(newRepo) > 0 {
+		defaultPypiRepo = newRepo
+	}
+}
+
+// pypiOrigin 获取满足版本约束的最高版本组件
+var pypiOrigin = func(name, spec string) *PyDist {
+
+	project := pypiLoad(name, "")
+	if project == nil {
+		return nil
+	}
+
+	version := maxPyVersion(project.versions(), spec)
+	if version == "" {
+		return nil
+	}
+
+	if data := pypiLoad(name, version); data != nil {
+		return data.dist()
+	}
+	return nil
+}
+
+// RegisterPypiOrigin 注册pypi数据源
+func RegisterPypiOrigin(origin func(name, spec string) *PyDist) {
+	if origin != nil {
+		pypiOrigin = origin
+	}
+}
+
+// pypiLoad 读取缓存或从仓库下载组件信息
+// version: 为空时获取组件的版本列表 否则获取对应版本的信息
+func pypiLoad(name, version string) *pypiJson {
+
+	var data *pypiJson
+
+	// 读取缓存
+	path := cache.Path("", normalizeName(name), version, model.Lan_Python)
+	cache.Load(path, func(reader io.Reader) {
+		data = readPypiJson(reader)
+	})
+	if data != nil {
+		return data
+	}
+
+	for _, repo := range defaultPypiRepo {
+		if strings.HasSuffix(strings.TrimRight(repo.Url, "/"), "/simple") {
+			data = pypiSimple(repo, name, version)
+		} else {
+			route := fmt.Sprintf("%s/json", normalizeName(name))
+			if version != "" {
+				route = fmt.Sprintf("%s/%s/json", normalizeName(name), version)
+			}
+			common.DownloadUrlFromRepos(route, func(repo common.RepoConfig, r io.Reader) {
+				data = readPypiJson(r)
+			}, repo)
+		}
+		if data != nil {
+			break
+		}
+	}
+
+	// 仅缓存需要的字段
+	if data != nil {
+		if b, err := json.Marshal(data); err == nil {
+			cache.Save(path, bytes.NewReader(b))
+		}
+	}
+
+	return data
+}
+
+func readPypiJson(reader io.Reader) *pypiJson {
+	data := &pypiJson{}
+	if err := json.NewDecoder(reader).Decode(data); err != nil {
+		logs.Warn(err)
+		return nil
+	}
+	return data
+}
+
+var (
+	pypiLinkReg = regexp.MustCompile(`(?is)<a\s([^>]*)>([^<]*)</a>`)
+	pypiAttrReg = regexp.MustCompile(`(?is)([a-z-]+)\s*=\s*"([^"]*)"`)
+)
+
+// pypiSimple 通过simple api获取组件信息
+// https://packaging.python.org/en/latest/specifications/simple-repository-api/
+func pypiSimple(repo common.RepoConfig, name, version string) *pypiJson {
+
+	var index string
+	common.DownloadUrlFromRepos(normalizeName(name)+"/", func(repo common.RepoConfig, r io.Reader) {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			logs.Warn(err)
+			return
+		}
+		index = string(b)
+	}, repo)
+	if index == "" {
+		return nil
+	}
+
+	data := &pypiJson{Releases: map[string][]pypiFile{}}
+
+	// 版本对应的元数据文件地址 PEP 658
+	var metadata string
+	for _, link := range pypiLinkReg.FindAllStringSubmatch(index, -1) {
+		attrs := map[string]string{}
+		for _, attr := range pypiAttrReg.FindAllStringSubmatch(link[1], -1) {
+			attrs[strings.ToLower(attr[1])] = html.UnescapeString(attr[2])
+		}
+		filename := strings.TrimSpace(html.UnescapeString(link[2]))
+		v := pypiFileVersion(name, filename)
+		if v == "" {
+			continue
+		}
+		_, yanked := attrs["data-yanked"]
+		data.Releases[v] = append(data.Releases[v], pypiFile{Yanked: yanked})
+		if v != version || metadata != "" || !strings.HasSuffix(strings.ToLower(filename), ".whl") {
+			continue
+		}
+		core, ok := attrs["data-core-metadata"]
+		if !ok {
+			core, ok = attrs["data-dist-info-metadata"]
+		}
+		if ok && core != "false" {
+			metadata = attrs["href"]
+		}
+	}
+
+	if version == "" {
+		return data
+	}
+	if _, ok := data.Releases[version]; !ok {
+		return nil
+	}
+
+	data.Releases = nil
+	data.Info.Name = name
+	data.Info.Version = version
+	if metadata == "" {
+		return data
+	}
+
+	// 元数据文件地址为相对于组件页面的地址
+	base, err := url.Parse(fmt.Sprintf("%s/%s/", strings.TrimRight(repo.Url, "/"), normalizeName(name)))
+	if err != nil {
+		return data
+	}
+	ref, err := url.Parse(metadata)
+	if err != nil {
+		return data
+	}
+	link := base.ResolveReference(ref)
+	link.Fragment = ""
+	// 仅向仓库所在域名发送认证信息
+	if link.Host != base.Host {
+		repo.Username, repo.Password = "", ""
+	}
+	dir, file := link.String(), ""
+	if i := strings.LastIndex(dir, "/"); i != -1 {
+		dir, file = dir[:i], dir[i+1:]
+	}
+	repo.Url = dir
+	common.DownloadUrlFromRepos(file+".metadata", func(repo common.RepoConfig, r io.Reader) {
+		headers := readMetadata(r)
+		first := func(key string) string {
+			if values := headers[key]; len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		}
+		if n := first("name"); n != "" {
+			data.Info.Name = n
+		}
+		data.Info.License = first("license")
+		data.Info.LicenseExpression = first("license-expression")
+		data.Info.Classifiers = headers["classifier"]
+		data.Info.RequiresDist = headers["requires-dist"]
+	}, repo)
+
+	return data
+}
+
+// pypiFileVersion 通过发布文件名获取版本号
+// requests-2.31.0-py3-none-any.whl requests-2.31.0.tar.gz
+func pypiFileVersion(name, filename string) string {
+
+	lower := strings.ToLower(filename)
+	for _, ext := range []string{".whl", ".egg", ".tar.gz", ".tar.bz2", ".tgz", ".zip"} {
+		if !strings.HasSuffix(lower, ext) {
+			continue
+		}
+		base := filename[:len(filename)-len(ext)]
+		if ext == ".whl" || ext == ".egg" {
+			if s := strings.Split(base, "-"); len(s) > 1 {
+				return s[1]
+			}
+			return ""
+		}
+		// sdist的组件名中可能包含-
+		for i := range base {
+			if base[i] == '-' && normalizeName(base[:i]) == normalizeName(name) {
+				return base[i+1:]
+			}
+		}
+	}
+	return ""
+}
+
+// resolvePypi 通过pypi解析直接依赖的版本约束及间接依赖
+func resolvePypi(root *model.DepGraph) *model.DepGraph {
+
+	_dep := model.NewDepGraphMap(func(s ...string) string {
+		return normalizeName(s[0]) + ":" + strings.Join(s[1:], ":")
+	}, func(s ...string) *model.DepGraph {
+		return &model.DepGraph{
+			Name:    s[0],
+			Version: s[1],
+			Develop: len(s) > 2 && s[2] == "dev",
+		}
+	}).LoadOrStore
+
+	// 已解析的组件 满足约束时优先使用 key:规范化的组件名
+	resolved := map[string][]*PyDist{}
+	// 无法解析的约束 key:规范化的组件名:版本约束
+	failed := map[string]bool{}
+
+	origin := func(name, spec string) *PyDist {
+		if s, ok := parsePySpecifier(spec); ok {
+			for _, dist := range resolved[normalizeName(name)] {
+				if s.match(dist.Version) {
+					return dist
+				}
+			}
+		}
+		key := normalizeName(name) + ":" + spec
+		if failed[key] {
+			return nil
+		}
+		dist := pypiOrigin(name, spec)
+		if dist == nil {
+			logs.Debugf("pypi resolve %s %s fail", name, spec)
+			failed[key] = true
+			return nil
+		}
+		resolved[normalizeName(name)] = append(resolved[normalizeName(name)], dist)
+		return dist
+	}
+
+	find := func(name, spec string, dev bool) *model.DepGraph {
+		dist := origin(name, spec)
+		// 无法解析时保留版本约束
+		if dist == nil {
+			return &model.DepGraph{Name: name, Version: spec, Develop: dev}
+		}
+		var dep *model.DepGraph
+		if dev {
+			dep = _dep(dist.Name, dist.Version, "dev")
+		} else {
+			dep = _dep(dist.Name, dist.Version)
+		}
+		if dep.Expand == nil {
+			dep.Expand = dist
+			dep.AppendLicense(dist.License)
+		}
+		return dep
+	}
+
+	// 组件启用的extras
+	extras := map[*model.DepGraph]map[string]bool{}
+	// 待解析子依赖的组件 启用新的extra时需重新解析
+	var queue []*model.DepGraph
+	enable := func(dep *model.DepGraph, names []string) {
+		if _, ok := dep.Expand.(*PyDist); !ok {
+			return
+		}
+		set, ok := extras[dep]
+		if !ok {
+			set = map[string]bool{}
+			extras[dep] = set
+			queue = append(queue, dep)
+		}
+		for _, name := range names {
+			if name = normalizeName(name); !set[name] {
+				set[name] = true
+				if ok {
+					queue = append(queue, dep)
+					ok = false
+				}
+			}
+		}
+	}
+
+	// 直接依赖的Expand为需要启用的extras
+	direct := make([]*model.DepGraph, len(root.Children))
+	copy(direct, root.Children)
+	for _, child := range direct {
+		// 运行时组件无需解析
+		if child.Language == model.Lan_Runtime {
+			continue
+		}
+		names, _ := child.Expand.([]string)
+		root.RemoveChild(child)
+		dep := find(child.Name, child.Version, child.Develop)
+		root.AppendChild(dep)
+		enable(dep, names)
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		dist := n.Expand.(*PyDist)
+		for _, spec := range dist.Requires {
+			req := parseRequirement(spec)
+			if req == nil || !pyEnv.match(req.Marker, sortedKeys(extras[n])) {
+				continue
+			}
+			// extra引用组件自身的其他extra 如 pkg[all] 依赖 pkg[a,b]
+			if normalizeName(req.Name) == normalizeName(n.Name) {
+				enable(n, req.Extras)
+				continue
+			}
+			// 开发依赖的子依赖同样仅用于开发环境
+			dep := find(req.Name, req.Version, n.Develop)
+			n.AppendChild(dep)
+			enable(dep, req.Extras)
+		}
+	}
+
+	root.ForEachNode(func(p, n *model.DepGraph) bool {
+		n.Expand = nil
+		return true
+	})
+
+	return root
+}
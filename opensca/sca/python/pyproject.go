@@ -0,0 +1,376 @@
+package python
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PyProject pyproject.toml
+// https://packaging.python.org/en/latest/specifications/pyproject-toml/
+type PyProject struct {
+	// PEP 621
+	Project struct {
+		Name                 string              `toml:"name"`
+		Version              string              `toml:"version"`
+		RequiresPython       string              `toml:"requires-python"`
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+	} `toml:"project"`
+	// PEP 735 值可能为字符串或{include-group = "x"}
+	DependencyGroups map[string][]any `toml:"dependency-groups"`
+	Tool             struct {
+		Poetry struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+			// value: "^1.0" | {version = "^1.0", optional = true} | [{...}]
+			Dependencies    map[string]any `toml:"dependencies"`
+			DevDependencies map[string]any `toml:"dev-dependencies"`
+			Group           map[string]struct {
+				Optional     bool           `toml:"optional"`
+				Dependencies map[string]any `toml:"dependencies"`
+			} `toml:"group"`
+		} `toml:"poetry"`
+		Pdm struct {
+			DevDependencies map[string][]string `toml:"dev-dependencies"`
+		} `toml:"pdm"`
+		Uv struct {
+			DevDependencies []string `toml:"dev-dependencies"`
+		} `toml:"uv"`
+	} `toml:"tool"`
+	File *model.File `toml:"-"`
+}
+
+// pyRequire 项目声明的直接依赖
+type pyRequire struct {
+	Name    string
+	Version string
+	// 开发依赖 如poetry的group及pdm/uv的dev-dependencies
+	Develop bool
+	// 可选依赖 如optional-dependencies(extras)
+	Optional bool
+}
+
+// ReadPyProject 读取pyproject.toml
+func ReadPyProject(file *model.File) *PyProject {
+	var pyproject *PyProject
+	file.OpenReader(func(reader io.Reader) {
+		pyproject = &PyProject{File: file}
+		if _, err := toml.NewDecoder(reader).Decode(pyproject); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+			pyproject = nil
+		}
+	})
+	return pyproject
+}
+
+// PythonVersion 项目声明的python版本约束 poetry中为python依赖
+func (p *PyProject) PythonVersion() string {
+	if p.Project.RequiresPython != "" {
+		return p.Project.RequiresPython
+	}
+	version, _ := poetrySpec(p.Tool.Poetry.Dependencies["python"])
+	return version
+}
+
+// Name 项目名称
+func (p *PyProject) Name() string {
+	if p.Project.Name != "" {
+		return p.Project.Name
+	}
+	return p.Tool.Poetry.Name
+}
+
+// Version 项目版本
+func (p *PyProject) Version() string {
+	if p.Project.Version != "" {
+		return p.Project.Version
+	}
+	return p.Tool.Poetry.Version
+}
+
+// Requires 项目声明的直接依赖
+func (p *PyProject) Requires() []pyRequire {
+
+	var requires []pyRequire
+
+	add := func(spec string, develop, optional bool) {
+		if name, version := splitRequirement(spec); name != "" {
+			requires = append(requires, pyRequire{Name: name, Version: version, Develop: develop, Optional: optional})
+		}
+	}
+
+	addPoetry := func(deps map[string]any, develop, optional bool) {
+		for _, name := range sortedKeys(deps) {
+			// python为运行环境
+			if strings.EqualFold(name, "python") {
+				continue
+			}
+			version, opt := poetrySpec(deps[name])
+			requires = append(requires, pyRequire{Name: name, Version: version, Develop: develop, Optional: optional || opt})
+		}
+	}
+
+	for _, spec := range p.Project.Dependencies {
+		add(spec, false, false)
+	}
+	for _, extra := range sortedKeys(p.Project.OptionalDependencies) {
+		for _, spec := range p.Project.OptionalDependencies[extra] {
+			add(spec, false, true)
+		}
+	}
+	for _, group := range sortedKeys(p.DependencyGroups) {
+		for _, spec := range p.DependencyGroups[group] {
+			// {include-group = "x"} 引用的组会单独处理
+			if s, ok := spec.(string); ok {
+				add(s, true, false)
+			}
+		}
+	}
+
+	addPoetry(p.Tool.Poetry.Dependencies, false, false)
+	addPoetry(p.Tool.Poetry.DevDependencies, true, false)
+	for _, group := range sortedKeys(p.Tool.Poetry.Group) {
+		addPoetry(p.Tool.Poetry.Group[group].Dependencies, true, false)
+	}
+
+	for _, group := range sortedKeys(p.Tool.Pdm.DevDependencies) {
+		for _, spec := range p.Tool.Pdm.DevDependencies[group] {
+			add(spec, true, false)
+		}
+	}
+	for _, spec := range p.Tool.Uv.DevDependencies {
+		add(spec, true, false)
+	}
+
+	return requires
+}
+
+// ParsePyProject 解析pyproject.toml中声明的直接依赖 仅在没有lock文件时使用
+func ParsePyProject(pyproject *PyProject) *model.DepGraph {
+
+	root := &model.DepGraph{Name: pyproject.Name(), Version: pyproject.Version(), Path: pyproject.File.Relpath()}
+
+	_dep := model.NewDepGraphMap(func(s ...string) string { return normalizeName(s[0]) }, func(s ...string) *model.DepGraph {
+		return &model.DepGraph{Name: s[0], Version: s[1]}
+	}).LoadOrStore
+
+	// 同一组件同时为生产及开发依赖时以生产依赖为准
+	requires := pyproject.Requires()
+	prod := map[string]bool{}
+	required := map[string]bool{}
+	for _, r := range requires {
+		if !r.Develop {
+			prod[normalizeName(r.Name)] = true
+		}
+		if !r.Optional {
+			required[normalizeName(r.Name)] = true
+		}
+	}
+
+	for _, r := range requires {
+		dep := _dep(r.Name, r.Version)
+		dep.Develop = !prod[normalizeName(r.Name)]
+		dep.Optional = !required[normalizeName(r.Name)]
+		root.AppendChild(dep)
+	}
+
+	return root
+}
+
+// poetrySpec 解析poetry依赖声明
+func poetrySpec(v any) (version string, optional bool) {
+	switch spec := v.(type) {
+	case string:
+		return spec, false
+	case map[string]any:
+		version, _ = spec["version"].(string)
+		optional, _ = spec["optional"].(bool)
+		return
+	case []any:
+		// 多约束依赖 取第一个
+		if len(spec) > 0 {
+			return poetrySpec(spec[0])
+		}
+	case []map[string]any:
+		if len(spec) > 0 {
+			return poetrySpec(spec[0])
+		}
+	}
+	return "", false
+}
+
+var requirementNameReg = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+// pyRequirement PEP 508依赖声明
+type pyRequirement struct {
+	Name    string
+	Version string
+	Extras  []string
+	Marker  string
+}
+
+// parseRequirement 解析PEP 508依赖声明 requests[security] (>=2.8.1) ; python_version < "2.7"
+func parseRequirement(spec string) *pyRequirement {
+
+	req := &pyRequirement{}
+	if i := strings.Index(spec, ";"); i != -1 {
+		spec, req.Marker = spec[:i], strings.TrimSpace(spec[i+1:])
+	}
+	spec = strings.TrimSpace(spec)
+
+	req.Name = requirementNameReg.FindString(spec)
+	if req.Name == "" {
+		return nil
+	}
+	rest := strings.TrimSpace(spec[len(req.Name):])
+	// extras
+	if strings.HasPrefix(rest, "[") {
+		if i := strings.Index(rest, "]"); i != -1 {
+			for _, extra := range strings.Split(rest[1:i], ",") {
+				if extra = strings.TrimSpace(extra); extra != "" {
+					req.Extras = append(req.Extras, extra)
+				}
+			}
+			rest = strings.TrimSpace(rest[i+1:])
+		}
+	}
+	// url依赖
+	if strings.HasPrefix(rest, "@") {
+		return req
+	}
+	rest = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(rest, "("), ")"))
+	rest = strings.ReplaceAll(rest, " ", "")
+	if strings.HasPrefix(rest, "==") && !strings.Contains(rest, ",") {
+		rest = strings.TrimPrefix(rest, "==")
+	}
+	req.Version = rest
+	return req
+}
+
+// splitRequirement 解析依赖声明中的组件名及版本约束
+func splitRequirement(spec string) (name, version string) {
+	if req := parseRequirement(spec); req != nil {
+		return req.Name, req.Version
+	}
+	return
+}
+
+var normalizeReg = regexp.MustCompile(`[-_.]+`)
+
+// normalizeName 规范化组件名 PEP 503
+func normalizeName(name string) string {
+	return strings.ToLower(normalizeReg.ReplaceAllString(name, "-"))
+}
+
+// sortedKeys 按key排序 保证检出顺序稳定
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pyLockGraph 锁文件中的依赖关系
+type pyLockGraph struct {
+	// key: 规范化的组件名及版本
+	nodes map[string]*model.DepGraph
+	// key: 规范化的组件名
+	names map[string]*model.DepGraph
+}
+
+func newPyLockGraph() *pyLockGraph {
+	return &pyLockGraph{nodes: map[string]*model.DepGraph{}, names: map[string]*model.DepGraph{}}
+}
+
+// node 获取或创建锁定的组件
+func (g *pyLockGraph) node(name, version string) *model.DepGraph {
+	key := fmt.Sprintf("%s@%s", normalizeName(name), version)
+	dep, ok := g.nodes[key]
+	if !ok {
+		dep = &model.DepGraph{Name: name, Version: version}
+		g.nodes[key] = dep
+		if _, ok := g.names[normalizeName(name)]; !ok {
+			g.names[normalizeName(name)] = dep
+		}
+	}
+	return dep
+}
+
+// find 通过组件名查找锁定的组件 version为空时返回第一个同名组件
+func (g *pyLockGraph) find(name, version string) *model.DepGraph {
+	if version != "" {
+		if dep, ok := g.nodes[fmt.Sprintf("%s@%s", normalizeName(name), version)]; ok {
+			return dep
+		}
+	}
+	return g.names[normalizeName(name)]
+}
+
+// roots 没有被其他组件依赖的组件
+func (g *pyLockGraph) roots() []*model.DepGraph {
+	var roots []*model.DepGraph
+	for _, key := range sortedKeys(g.nodes) {
+		if dep := g.nodes[key]; len(dep.Parents) == 0 {
+			roots = append(roots, dep)
+		}
+	}
+	return roots
+}
+
+// build 添加直接依赖并标记开发及可选组件
+// 仅能通过开发依赖引入的组件为开发组件 仅能通过可选依赖引入的组件为可选组件
+func (g *pyLockGraph) build(root *model.DepGraph, requires []pyRequire) *model.DepGraph {
+
+	reach := func(match func(r pyRequire) bool) map[*model.DepGraph]bool {
+		set := map[*model.DepGraph]bool{}
+		for _, r := range requires {
+			if dep := g.find(r.Name, r.Version); dep != nil && match(r) {
+				dep.ForEachNode(func(p, n *model.DepGraph) bool {
+					if set[n] {
+						return false
+					}
+					set[n] = true
+					return true
+				})
+			}
+		}
+		return set
+	}
+
+	// 锁文件中无法通过直接依赖引入的组件(如通过extra引入)作为可选依赖
+	all := reach(func(r pyRequire) bool { return true })
+	for _, key := range sortedKeys(g.nodes) {
+		if dep := g.nodes[key]; !all[dep] && len(dep.Parents) == 0 {
+			requires = append(requires, pyRequire{Name: dep.Name, Version: dep.Version, Optional: true})
+		}
+	}
+
+	prod := reach(func(r pyRequire) bool { return !r.Develop })
+	required := reach(func(r pyRequire) bool { return !r.Optional })
+
+	for _, r := range requires {
+		if dep := g.find(r.Name, r.Version); dep != nil {
+			root.AppendChild(dep)
+		}
+	}
+
+	root.ForEachNode(func(p, n *model.DepGraph) bool {
+		if p != nil {
+			n.Develop = !prod[n]
+			n.Optional = !required[n]
+		}
+		return true
+	})
+
+	return root
+}
@@ -20,7 +20,13 @@ func (sca Sca) Filter(relpath string) bool {
 		filter.PythonPipfile(relpath) ||
 		filter.PythonRequirementsIn(relpath) ||
 		filter.PythonRequirementsTxt(relpath) ||
-		filter.PythonSetup(relpath)
+		filter.PythonSetup(relpath) ||
+		filter.PythonPyProject(relpath) ||
+		filter.PythonPoetryLock(relpath) ||
+		filter.PythonPdmLock(relpath) ||
+		filter.PythonUvLock(relpath) ||
+		filter.PythonDistMetadata(relpath) ||
+		filter.PythonDistRequires(relpath)
 }
 
 func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
@@ -35,43 +41,81 @@ func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File,
 		}
 	}
 
-	// 记录使用pipenv解析过的目录
-	pipSet := map[string]bool{}
-	// 尝试使用pipenv解析
-	for _, file := range files {
-		if pipSet[path2dir(file.Relpath())] {
-			continue
-		}
-		if filter.PythonPipfile(file.Relpath()) ||
-			filter.PythonRequirementsTxt(file.Relpath()) {
-		} else {
-			continue
-		}
-		root := ParsePythonWithEnv(ctx, file)
-		if root == nil {
-			continue
-		}
-		call(file, root)
-		pipSet[path2dir(file.Relpath())] = true
-	}
+	// 解析poetry/pdm/uv项目
+	parsePyProjects(files, call)
 
-	// 静态解析
+	// 解析已安装的组件及wheel/egg包
+	parsePyDists(files, call)
+
+	// 静态解析 版本约束通过pypi解析
 	for _, file := range files {
-		if pipSet[path2dir(file.Relpath())] {
-			continue
-		}
 		if filter.PythonPipfile(file.Relpath()) {
 			if !lockSet[path2dir(file.Relpath())] {
-				call(file, ParsePipfile(file))
+				call(file, resolvePypi(ParsePipfile(file)))
 			}
 		} else if filter.PythonPipfileLock(file.Relpath()) {
 			call(file, ParsePipfileLock(file))
 		} else if filter.PythonRequirementsIn(file.Relpath()) {
-			call(file, ParseRequirementIn(file))
+			call(file, resolvePypi(ParseRequirementIn(file)))
 		} else if filter.PythonRequirementsTxt(file.Relpath()) {
-			call(file, ParseRequirementTxt(file))
+			call(file, resolvePypi(ParseRequirementTxt(file)))
 		} else if filter.PythonSetup(file.Relpath()) {
-			call(file, ParseSetup(file))
+			call(file, resolvePypi(ParseSetup(file)))
+		}
+	}
+}
+
+// parsePyProjects 解析poetry.lock/pdm.lock/uv.lock 没有lock文件时使用pyproject.toml
+func parsePyProjects(files []*model.File, call model.ResCallback) {
+
+	path2dir := func(relpath string) string { return path.Dir(strings.ReplaceAll(relpath, `\`, `/`)) }
+
+	// map[dirpath]
+	pyprojects := map[string]*PyProject{}
+	for _, file := range files {
+		if filter.PythonPyProject(file.Relpath()) {
+			if pyproject := ReadPyProject(file); pyproject != nil {
+				pyprojects[path2dir(file.Relpath())] = pyproject
+			}
+		}
+	}
+
+	// 记录已由lock文件解析的目录
+	lockSet := map[string]bool{}
+	for _, file := range files {
+		dir := path2dir(file.Relpath())
+		// 记录pyproject.toml中声明的python版本
+		call := func(file *model.File, root *model.DepGraph) {
+			if pyproject, ok := pyprojects[dir]; ok {
+				root.AppendRuntime(model.Runtime_Python, pyproject.PythonVersion())
+			}
+			call(file, root)
+		}
+		switch {
+		case filter.PythonPoetryLock(file.Relpath()):
+			call(file, ParsePoetryLock(file, pyprojects[dir]))
+		case filter.PythonPdmLock(file.Relpath()):
+			call(file, ParsePdmLock(file, pyprojects[dir]))
+		case filter.PythonUvLock(file.Relpath()):
+			lock := ReadUvLock(file)
+			call(file, ParseUvLock(file, lock))
+			// workspace中的其他项目已记录在uv.lock中
+			for _, pkg := range lock.Packages {
+				if member, ok := pkg.member(); ok {
+					lockSet[path.Join(dir, member)] = true
+				}
+			}
+		default:
+			continue
+		}
+		lockSet[dir] = true
+	}
+
+	for dir, pyproject := range pyprojects {
+		if !lockSet[dir] {
+			root := ParsePyProject(pyproject)
+			root.AppendRuntime(model.Runtime_Python, pyproject.PythonVersion())
+			call(pyproject.File, root)
 		}
 	}
 }
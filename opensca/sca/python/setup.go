@@ -1,6 +1,7 @@
 package python
 
 import (
+	"bytes"
 	_ "embed"
 	"encoding/json"
 	"io"
@@ -14,44 +15,65 @@ import (
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 )
 
+// setupExec 是否允许调用python执行setup.py
+var setupExec bool
+
+// RegisterSetupExec 设置是否允许调用python执行setup.py 默认不执行项目代码
+func RegisterSetupExec(enable bool) {
+	setupExec = enable
+}
+
 // ParseSetup 解析setup.py
 func ParseSetup(file *model.File) *model.DepGraph {
 
-	// 尝试调用python解析
-	root := ParseSetupPyWithPython(file)
-	if root != nil && len(root.Children) > 0 {
-		return root
+	var data []byte
+	file.OpenReader(func(reader io.Reader) {
+		data, _ = io.ReadAll(reader)
+	})
+
+	// 默认仅静态解析 显式启用时才执行setup.py
+	var root *model.DepGraph
+	if setupExec {
+		root = ParseSetupPyWithPython(file)
+	}
+	if root == nil || len(root.Children) == 0 {
+		root = parseSetupStatic(file, data)
 	}
 
-	root = &model.DepGraph{Path: file.Relpath()}
+	// 项目声明的python版本
+	if version := setupPythonRequiresReg.FindSubmatch(data); len(version) > 1 {
+		root.AppendRuntime(model.Runtime_Python, string(version[1]))
+	}
 
-	// 静态解析
-	file.OpenReader(func(reader io.Reader) {
-		data, err := io.ReadAll(reader)
-		if err != nil {
-			return
-		}
-		reg := regexp.MustCompile(`install_requires\s*=\s*\[([^\]]+)\]`)
-		requires := reg.FindStringSubmatch(string(data))
-		if len(requires) < 2 {
-			return
+	return root
+}
+
+var setupPythonRequiresReg = regexp.MustCompile(`python_requires\s*=\s*['"]([^'"]+)['"]`)
+
+// parseSetupStatic 静态解析setup.py中的install_requires
+func parseSetupStatic(file *model.File, data []byte) *model.DepGraph {
+
+	root := &model.DepGraph{Path: file.Relpath()}
+
+	reg := regexp.MustCompile(`install_requires\s*=\s*\[([^\]]+)\]`)
+	requires := reg.FindSubmatch(data)
+	if len(requires) < 2 {
+		return root
+	}
+
+	model.ReadLineNoComment(bytes.NewReader(requires[1]), model.PythonTypeComment, func(line string) {
+		// 仅去除外层引号 保留环境标记中的引号
+		line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if len(line) > 1 && (line[0] == '\'' || line[0] == '"') && line[len(line)-1] == line[0] {
+			line = line[1 : len(line)-1]
 		}
-		model.ReadLineNoComment(strings.NewReader(requires[1]), model.PythonTypeComment, func(line string) {
-			line = strings.Trim(strings.TrimSpace(line), `'",`)
-			words := strings.Fields(line)
-			if len(words) == 0 {
-				return
-			}
-			name := words[0]
-			var version string
-			if len(words) > 1 {
-				version = strings.Join(words[1:], "")
-			}
+		if req := parseRequirement(line); req != nil && pyEnv.match(req.Marker, pyEnv.Extras) {
 			root.AppendChild(&model.DepGraph{
-				Name:    name,
-				Version: version,
+				Name:    req.Name,
+				Version: req.Version,
+				Expand:  req.Extras,
 			})
-		})
+		}
 	})
 
 	return root
@@ -84,6 +106,7 @@ func ParseSetupPyWithPython(file *model.File) *model.DepGraph {
 		logs.Warn(err)
 		return nil
 	}
+	defer os.Remove(ossfile)
 
 	// 解析 setup.py
 	cmd := exec.Command("python", ossfile, file.Abspath())
@@ -108,15 +131,9 @@ func ParseSetupPyWithPython(file *model.File) *model.DepGraph {
 
 	for _, pkg := range [][]string{dep.Packages, dep.InstallRequires, dep.Requires} {
 		for _, p := range pkg {
-			index := strings.IndexAny(p, "=<>")
-			var name, version string
-			if index > -1 {
-				name = p[:index]
-				version = p[index:]
-			} else {
-				name = p
+			if req := parseRequirement(p); req != nil && pyEnv.match(req.Marker, pyEnv.Extras) {
+				root.AppendChild(&model.DepGraph{Name: req.Name, Version: req.Version, Expand: req.Extras})
 			}
-			root.AppendChild(&model.DepGraph{Name: name, Version: version})
 		}
 	}
 
@@ -0,0 +1,113 @@
+package python
+
+import (
+	"io"
+	"path"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+
+	"github.com/BurntSushi/toml"
+)
+
+// UvLock uv.lock
+type UvLock struct {
+	Packages []*UvPackage `toml:"package"`
+}
+
+// UvPackage uv锁定的组件
+type UvPackage struct {
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+	// {registry = "..."} | {editable = "."} | {virtual = "."} ...
+	Source struct {
+		Editable string `toml:"editable"`
+		Virtual  string `toml:"virtual"`
+	} `toml:"source"`
+	Dependencies         []uvDependency            `toml:"dependencies"`
+	OptionalDependencies map[string][]uvDependency `toml:"optional-dependencies"`
+	DevDependencies      map[string][]uvDependency `toml:"dev-dependencies"`
+}
+
+// uvDependency 依赖引用 同名组件存在多个版本时会记录版本号
+type uvDependency struct {
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+}
+
+// member workspace中的项目 返回项目相对uv.lock的路径
+func (pkg *UvPackage) member() (string, bool) {
+	if pkg.Source.Editable != "" {
+		return path.Clean(pkg.Source.Editable), true
+	}
+	if pkg.Source.Virtual != "" {
+		return path.Clean(pkg.Source.Virtual), true
+	}
+	return "", false
+}
+
+// ReadUvLock 读取uv.lock
+func ReadUvLock(file *model.File) *UvLock {
+	lock := &UvLock{}
+	file.OpenReader(func(reader io.Reader) {
+		if _, err := toml.NewDecoder(reader).Decode(lock); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+	return lock
+}
+
+// ParseUvLock 解析uv.lock 根项目为source指向uv.lock所在目录的组件
+func ParseUvLock(file *model.File, lock *UvLock) *model.DepGraph {
+
+	g := newPyLockGraph()
+	var project *UvPackage
+	for _, pkg := range lock.Packages {
+		dir, ok := pkg.member()
+		if ok && dir == "." {
+			project = pkg
+			continue
+		}
+		// workspace中的其他项目
+		g.node(pkg.Name, pkg.Version).Local = ok
+	}
+
+	for _, pkg := range lock.Packages {
+		if pkg == project {
+			continue
+		}
+		dep := g.node(pkg.Name, pkg.Version)
+		// optional-dependencies仅在启用extra时引入
+		for _, d := range pkg.Dependencies {
+			if sub := g.find(d.Name, d.Version); sub != nil {
+				dep.AppendChild(sub)
+			}
+		}
+	}
+
+	root := &model.DepGraph{Path: file.Relpath()}
+
+	var requires []pyRequire
+	if project != nil {
+		root.Name, root.Version = project.Name, project.Version
+		for _, d := range project.Dependencies {
+			requires = append(requires, pyRequire{Name: d.Name, Version: d.Version})
+		}
+		for _, extra := range sortedKeys(project.OptionalDependencies) {
+			for _, d := range project.OptionalDependencies[extra] {
+				requires = append(requires, pyRequire{Name: d.Name, Version: d.Version, Optional: true})
+			}
+		}
+		for _, group := range sortedKeys(project.DevDependencies) {
+			for _, d := range project.DevDependencies[group] {
+				requires = append(requires, pyRequire{Name: d.Name, Version: d.Version, Develop: true})
+			}
+		}
+	} else {
+		for _, dep := range g.roots() {
+			requires = append(requires, pyRequire{Name: dep.Name, Version: dep.Version})
+		}
+	}
+
+	return g.build(root, requires)
+}
@@ -7,56 +7,124 @@ import (
 )
 
 // ParseGemfileLock 解析Gemfile.lock文件
-func ParseGemfileLock(file *model.File) *model.DepGraph {
+// https://bundler.io/guides/bundler_lockfile.html
+// gemfile: 同目录下的Gemfile 用于确定直接依赖的分组 可以为nil
+func ParseGemfileLock(file *model.File, gemfile *Gemfile) *model.DepGraph {
 
-	// map[name]dep
-	depMap := map[string]*model.DepGraph{}
+	_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph {
+		return &model.DepGraph{Name: s[0]}
+	}).LoadOrStore
 
-	space4 := "    "
-	space6 := "      "
+	// 解析 name (version) 版本中的平台后缀如 1.15.4-x86_64-linux 会被去除
 	parseLine := func(line string) (name, version string) {
 		line = strings.TrimSpace(line)
 		i := strings.Index(line, " ")
 		if i == -1 {
-			name = line
-			return
+			return strings.TrimSuffix(line, "!"), ""
 		}
 		name = line[:i]
-		version = strings.Trim(line[i+1:], "()")
+		version = line[i+1:]
+		if j := strings.Index(version, ")"); j != -1 {
+			version = version[:j]
+		}
+		version = strings.TrimPrefix(version, "(")
 		return
 	}
+	platform := func(version string) string {
+		if i := strings.Index(version, "-"); i != -1 {
+			return version[:i]
+		}
+		return version
+	}
+
+	// 锁定的组件 key:组件名
+	specs := map[string]*model.DepGraph{}
+	// 直接依赖
+	var direct []*model.DepGraph
+	// 当前所在的段落及组件
+	var section string
+	var last *model.DepGraph
 
-	// 第一次记录依赖信息
 	file.ReadLine(func(line string) {
-		if !strings.HasPrefix(line, space4) || strings.HasPrefix(line, space6) {
+
+		if strings.TrimSpace(line) == "" {
 			return
 		}
-		name, version := parseLine(line)
-		if version == "" {
+
+		// 段落标题没有缩进
+		if !strings.HasPrefix(line, " ") {
+			section = strings.TrimSpace(line)
+			last = nil
 			return
 		}
-		depMap[name] = &model.DepGraph{
-			Name:    name,
-			Version: version,
-		}
-	})
 
-	// 第二次记录依赖关系
-	var last string
-	file.ReadLine(func(line string) {
-		if strings.HasPrefix(line, space6) {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch section {
+		case "GEM", "GIT", "PATH", "PLUGIN SOURCE":
+			switch indent {
+			case 4:
+				name, version := parseLine(line)
+				last = _dep(name)
+				last.Version = platform(version)
+				last.Local = section == "PATH"
+				specs[name] = last
+			case 6:
+				name, _ := parseLine(line)
+				last.AppendChild(_dep(name))
+			}
+		case "DEPENDENCIES":
+			// rack-mini!表示来自GIT/PATH段落
 			name, _ := parseLine(line)
-			depMap[last].AppendChild(depMap[name])
-			return
-		}
-		if strings.HasPrefix(line, space4) {
-			last, _ = parseLine(line)
-			return
+			direct = append(direct, _dep(strings.TrimSuffix(name, "!")))
+		case "CHECKSUMS":
+			// rack (3.0.8) sha256=...
+			name, _ := parseLine(line)
+			fields := strings.Fields(line)
+			if dep, ok := specs[name]; ok && dep.Integrity == "" && len(fields) > 2 {
+				dep.Integrity = fields[len(fields)-1]
+			}
 		}
 	})
 
 	root := &model.DepGraph{Path: file.Relpath()}
-	for _, d := range depMap {
+
+	dev := map[string]bool{}
+	if gemfile != nil {
+		dev = gemfile.develop()
+	}
+
+	// 仅被开发依赖引用的组件为开发组件
+	var runtime []*model.DepGraph
+	for _, d := range direct {
+		// 当前平台未锁定的组件
+		if _, ok := specs[d.Name]; !ok {
+			continue
+		}
+		root.AppendChild(d)
+		if !dev[d.Name] {
+			runtime = append(runtime, d)
+		}
+	}
+	prod := map[*model.DepGraph]bool{}
+	for _, d := range runtime {
+		d.ForEachNode(func(p, n *model.DepGraph) bool {
+			if prod[n] {
+				return false
+			}
+			prod[n] = true
+			return true
+		})
+	}
+	for _, d := range root.Children {
+		d.ForEachNode(func(p, n *model.DepGraph) bool {
+			n.Develop = !prod[n]
+			return true
+		})
+	}
+
+	// 没有被引用的组件
+	for _, d := range specs {
 		if len(d.Parents) == 0 {
 			root.AppendChild(d)
 		}
@@ -0,0 +1,291 @@
+package ruby
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// gemRequire 项目声明的依赖
+type gemRequire struct {
+	Name string
+	// 版本约束 多个约束以", "分隔
+	Version string
+	Develop bool
+	// 通过path引用的本地组件
+	Local bool
+}
+
+// Gemfile Gemfile/gems.rb
+// https://bundler.io/guides/gemfile.html
+type Gemfile struct {
+	Requires []gemRequire
+	// 通过gemspec指令引用的gemspec
+	Gemspecs []*Gemspec
+	File     *model.File
+}
+
+// devGroups 仅用于开发环境的分组
+var devGroups = map[string]bool{"development": true, "test": true}
+
+var (
+	// gem "rails", "~> 7.1", group: :test
+	gemfileGemReg = regexp.MustCompile(`^gem[\s(]+(.*?)\)?$`)
+	// gemspec path: "sub"
+	gemfileGemspecReg = regexp.MustCompile(`^gemspec\b[\s(]*(.*?)\)?$`)
+	// group :development, :test do
+	gemfileGroupReg = regexp.MustCompile(`^group\b[\s(]*(.*?)\)?\s+do\b`)
+	// 以do开始的代码块 如 platforms :jruby do / source "x" do
+	rubyBlockReg = regexp.MustCompile(`\bdo(\s*\|[^|]*\|)?$`)
+	// 以end结束的条件/循环语句
+	rubyStatementReg = regexp.MustCompile(`^(if|unless|case|while|until|begin)\b`)
+	// 字符串字面量 "x" | 'x' | %q<x> 可带.freeze
+	rubyStringReg = regexp.MustCompile(`"([^"]*)"|'([^']*)'|%[qQ]?[<{(\[]([^>})\]]*)[>})\]]`)
+	// 符号 :test
+	rubySymbolReg = regexp.MustCompile(`:(\w+)`)
+	// 关键字参数 key: value | :key => value
+	rubyOptionReg = regexp.MustCompile(`^:?(\w+)\s*(?::|=>)\s*(.*)$`)
+)
+
+// rubyComment 去除行尾注释
+func rubyComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// rubyArgs 按顶层逗号拆分方法参数
+func rubyArgs(s string) []string {
+	var args []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{' || c == '(' || c == '<':
+			depth++
+		case c == ']' || c == '}' || c == ')' || c == '>':
+			// 忽略 => 中的 >
+			if c == '>' && i > 0 && s[i-1] == '=' {
+				continue
+			}
+			depth--
+		case c == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		args = append(args, last)
+	}
+	return args
+}
+
+// rubyStrings 参数中的字符串字面量
+func rubyStrings(arg string) []string {
+	var strs []string
+	for _, m := range rubyStringReg.FindAllStringSubmatch(arg, -1) {
+		strs = append(strs, m[1]+m[2]+m[3])
+	}
+	return strs
+}
+
+// rubyCall 解析方法调用参数
+// names: 位置参数中的字符串 options: 关键字参数
+func rubyCall(s string) (names []string, options map[string]string) {
+	options = map[string]string{}
+	for _, arg := range rubyArgs(s) {
+		if m := rubyOptionReg.FindStringSubmatch(arg); m != nil {
+			options[m[1]] = m[2]
+			continue
+		}
+		names = append(names, rubyStrings(arg)...)
+	}
+	return
+}
+
+// isDevGroups 是否全部为开发环境分组
+func isDevGroups(groups []string) bool {
+	if len(groups) == 0 {
+		return false
+	}
+	for _, g := range groups {
+		if !devGroups[g] {
+			return false
+		}
+	}
+	return true
+}
+
+// symbols 参数中的符号及字符串 如 [:development, "test"]
+func symbols(s string) []string {
+	var names []string
+	for _, m := range rubySymbolReg.FindAllStringSubmatch(s, -1) {
+		names = append(names, m[1])
+	}
+	return append(names, rubyStrings(s)...)
+}
+
+// ReadGemfile 读取Gemfile
+// gemspecs: 项目中的gemspec key:所在目录
+func ReadGemfile(file *model.File, gemspecs map[string][]*Gemspec) *Gemfile {
+
+	gemfile := &Gemfile{File: file}
+	dir := path.Dir(strings.ReplaceAll(file.Relpath(), `\`, `/`))
+
+	// 代码块的分组
+	stack := [][]string{}
+	groups := func() []string {
+		if len(stack) == 0 {
+			return nil
+		}
+		return stack[len(stack)-1]
+	}
+
+	file.ReadLine(func(line string) {
+
+		line = rubyComment(line)
+		if line == "" {
+			return
+		}
+
+		if line == "end" || strings.HasPrefix(line, "end ") || strings.HasPrefix(line, "end.") {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			return
+		}
+
+		if m := gemfileGroupReg.FindStringSubmatch(line); m != nil {
+			// 只取位置参数中的分组 忽略optional等选项
+			var names []string
+			for _, arg := range rubyArgs(m[1]) {
+				if !rubyOptionReg.MatchString(arg) {
+					names = append(names, symbols(arg)...)
+				}
+			}
+			stack = append(stack, names)
+			return
+		}
+
+		if m := gemfileGemReg.FindStringSubmatch(line); m != nil {
+			names, options := rubyCall(strings.TrimSuffix(m[1], " do"))
+			if len(names) == 0 {
+				return
+			}
+			g := groups()
+			if v, ok := options["group"]; ok {
+				g = symbols(v)
+			} else if v, ok := options["groups"]; ok {
+				g = symbols(v)
+			}
+			_, local := options["path"]
+			gemfile.Requires = append(gemfile.Requires, gemRequire{
+				Name:    names[0],
+				Version: strings.Join(names[1:], ", "),
+				Develop: isDevGroups(g),
+				Local:   local,
+			})
+		} else if m := gemfileGemspecReg.FindStringSubmatch(line); m != nil {
+			_, options := rubyCall(m[1])
+			specDir := dir
+			if v := rubyStrings(options["path"]); len(v) > 0 {
+				specDir = path.Join(dir, v[0])
+			}
+			name := ""
+			if v := rubyStrings(options["name"]); len(v) > 0 {
+				name = v[0]
+			}
+			for _, spec := range gemspecs[specDir] {
+				if name == "" || spec.Name == name {
+					gemfile.Gemspecs = append(gemfile.Gemspecs, spec)
+				}
+			}
+		}
+
+		// 其他代码块继承外层分组
+		if rubyBlockReg.MatchString(line) || rubyStatementReg.MatchString(line) {
+			stack = append(stack, groups())
+		}
+	})
+
+	return gemfile
+}
+
+// develop 仅用于开发环境的直接依赖 key:组件名
+func (g *Gemfile) develop() map[string]bool {
+	dev := map[string]bool{}
+	set := func(name string, develop bool) {
+		if d, ok := dev[name]; ok {
+			dev[name] = d && develop
+		} else {
+			dev[name] = develop
+		}
+	}
+	for _, req := range g.Requires {
+		set(req.Name, req.Develop)
+	}
+	for _, spec := range g.Gemspecs {
+		set(spec.Name, false)
+		for _, req := range spec.Requires {
+			if req.Develop {
+				set(req.Name, true)
+			}
+		}
+	}
+	return dev
+}
+
+// ParseGemfile 解析没有Gemfile.lock的Gemfile
+func ParseGemfile(gemfile *Gemfile) *model.DepGraph {
+
+	root := &model.DepGraph{Path: gemfile.File.Relpath()}
+
+	for _, spec := range gemfile.Gemspecs {
+		dep := ParseGemspec(spec)
+		dep.Local = true
+		// gemspec中的开发依赖为项目的开发依赖
+		for _, c := range append([]*model.DepGraph{}, dep.Children...) {
+			if c.Develop {
+				dep.RemoveChild(c)
+				root.AppendChild(c)
+			}
+		}
+		root.AppendChild(dep)
+	}
+
+	for _, req := range gemfile.Requires {
+		root.AppendChild(&model.DepGraph{
+			Name:    req.Name,
+			Version: req.Version,
+			Develop: req.Develop,
+			Local:   req.Local,
+		})
+	}
+
+	return root
+}
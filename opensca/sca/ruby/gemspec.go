@@ -0,0 +1,92 @@
+package ruby
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// Gemspec *.gemspec
+// https://guides.rubygems.org/specification-reference/
+type Gemspec struct {
+	Name string
+	// 版本号为常量等非字面量时为空
+	Version  string
+	Requires []gemRequire
+	File     *model.File
+}
+
+var (
+	// spec.name = "demo" | s.version = "1.0.0".freeze
+	gemspecAttrReg = regexp.MustCompile(`^\w+\.(name|version)\s*=\s*(.*)$`)
+	// spec.add_dependency "rack", ">= 2.0" | s.add_runtime_dependency(%q<rack>.freeze, [">= 2.0"])
+	gemspecDependencyReg = regexp.MustCompile(`^\w+\.(add_dependency|add_runtime_dependency|add_development_dependency)\b[\s(]*(.*?)\)?$`)
+)
+
+// ReadGemspec 读取gemspec
+func ReadGemspec(file *model.File) *Gemspec {
+
+	spec := &Gemspec{File: file}
+
+	file.ReadLine(func(line string) {
+
+		line = rubyComment(line)
+
+		if m := gemspecAttrReg.FindStringSubmatch(line); m != nil {
+			// 仅识别字符串字面量
+			if !rubyStringReg.MatchString(strings.TrimSuffix(m[2], ".freeze")) {
+				return
+			}
+			value := rubyStrings(m[2])[0]
+			if m[1] == "name" {
+				spec.Name = value
+			} else {
+				spec.Version = value
+			}
+			return
+		}
+
+		if m := gemspecDependencyReg.FindStringSubmatch(line); m != nil {
+			names, _ := rubyCall(m[2])
+			if len(names) == 0 {
+				return
+			}
+			spec.Requires = append(spec.Requires, gemRequire{
+				Name:    names[0],
+				Version: strings.Join(names[1:], ", "),
+				Develop: m[1] == "add_development_dependency",
+			})
+		}
+	})
+
+	// 未声明名称时使用文件名
+	if spec.Name == "" {
+		spec.Name = strings.TrimSuffix(path.Base(strings.ReplaceAll(file.Relpath(), `\`, `/`)), ".gemspec")
+	}
+
+	return spec
+}
+
+// dir gemspec所在目录
+func (s *Gemspec) dir() string {
+	return path.Dir(strings.ReplaceAll(s.File.Relpath(), `\`, `/`))
+}
+
+// ParseGemspec 解析gemspec
+func ParseGemspec(spec *Gemspec) *model.DepGraph {
+	root := &model.DepGraph{
+		Name:    spec.Name,
+		Version: spec.Version,
+		Path:    spec.File.Relpath(),
+	}
+	for _, req := range spec.Requires {
+		root.AppendChild(&model.DepGraph{
+			Name:    req.Name,
+			Version: req.Version,
+			Develop: req.Develop,
+		})
+	}
+	return root
+}
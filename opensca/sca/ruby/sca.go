@@ -2,6 +2,8 @@ package ruby
 
 import (
 	"context"
+	"path"
+	"strings"
 
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
@@ -14,13 +16,61 @@ func (sca Sca) Language() model.Language {
 }
 
 func (sca Sca) Filter(relpath string) bool {
-	return filter.RubyGemfileLock(relpath)
+	return filter.RubyGemfileLock(relpath) ||
+		filter.RubyGemfile(relpath) ||
+		filter.RubyGemspec(relpath)
 }
 
 func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
-	for _, file := range files {
-		if filter.RubyGemfileLock(file.Relpath()) {
-			call(file, ParseGemfileLock(file))
+
+	path2dir := func(relpath string) string { return path.Dir(strings.ReplaceAll(relpath, `\`, `/`)) }
+
+	// map[dir]
+	locks := map[string]*model.File{}
+	gemfiles := map[string]*model.File{}
+	gemspecs := map[string][]*Gemspec{}
+	for _, f := range files {
+		switch {
+		case filter.RubyGemfileLock(f.Relpath()):
+			locks[path2dir(f.Relpath())] = f
+		case filter.RubyGemfile(f.Relpath()):
+			gemfiles[path2dir(f.Relpath())] = f
+		case filter.RubyGemspec(f.Relpath()):
+			spec := ReadGemspec(f)
+			gemspecs[spec.dir()] = append(gemspecs[spec.dir()], spec)
+		}
+	}
+
+	// 已被Gemfile引用的gemspec
+	used := map[*Gemspec]bool{}
+
+	for dir, f := range gemfiles {
+		gemfile := ReadGemfile(f, gemspecs)
+		for _, spec := range gemfile.Gemspecs {
+			used[spec] = true
+		}
+		if lock, ok := locks[dir]; ok {
+			call(lock, ParseGemfileLock(lock, gemfile))
+			delete(locks, dir)
+		} else {
+			call(f, ParseGemfile(gemfile))
+		}
+	}
+
+	// 没有Gemfile的Gemfile.lock
+	for dir, f := range locks {
+		call(f, ParseGemfileLock(f, nil))
+		for _, spec := range gemspecs[dir] {
+			used[spec] = true
+		}
+	}
+
+	// 仅有gemspec的项目
+	for _, specs := range gemspecs {
+		for _, spec := range specs {
+			if !used[spec] {
+				call(spec.File, ParseGemspec(spec))
+			}
 		}
 	}
 }
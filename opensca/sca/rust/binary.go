@@ -0,0 +1,131 @@
+package rust
+
+import (
+	"bytes"
+	"compress/zlib"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/json"
+	"io"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+// auditableSection cargo-auditable写入依赖信息的段名
+// https://github.com/rust-secure-code/cargo-auditable/blob/master/PARSING.md
+const auditableSection = ".dep-v0"
+
+// auditableInfo .dep-v0中zlib压缩的依赖信息
+type auditableInfo struct {
+	Packages []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		// crates.io|local|git|registry|other
+		Source string `json:"source"`
+		// runtime|build 缺省为runtime
+		Kind string `json:"kind"`
+		// 依赖的组件在packages中的下标
+		Dependencies []int `json:"dependencies"`
+		Root         bool  `json:"root"`
+	} `json:"packages"`
+}
+
+// ParseRustBinary 解析cargo-auditable构建的rust可执行文件(ELF/PE/Mach-O)中嵌入的依赖信息
+// 不包含依赖信息的文件返回nil
+func ParseRustBinary(file *model.File) *model.DepGraph {
+
+	executable := false
+	file.OpenReader(func(reader io.Reader) {
+		executable = filter.ExecutableMagic(reader)
+	})
+	if !executable {
+		return nil
+	}
+
+	var data []byte
+	file.OpenReader(func(reader io.Reader) {
+		if r, ok := reader.(io.ReaderAt); ok {
+			data = readAuditableSection(r)
+		}
+	})
+	if data == nil {
+		return nil
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		return nil
+	}
+	defer zr.Close()
+
+	var info auditableInfo
+	if err := json.NewDecoder(zr).Decode(&info); err != nil {
+		logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		return nil
+	}
+
+	deps := make([]*model.DepGraph, len(info.Packages))
+	for i, pkg := range info.Packages {
+		deps[i] = &model.DepGraph{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Local:   pkg.Source == "local" && !pkg.Root,
+		}
+		if pkg.Kind == "build" {
+			deps[i].Scope = model.Scope_Build
+			deps[i].Develop = true
+		}
+	}
+
+	root := &model.DepGraph{Path: file.Relpath()}
+	for i, pkg := range info.Packages {
+		for _, j := range pkg.Dependencies {
+			if j >= 0 && j < len(deps) && j != i {
+				deps[i].AppendChild(deps[j])
+			}
+		}
+		if pkg.Root {
+			root.AppendChild(deps[i])
+		}
+	}
+
+	return root
+}
+
+// readAuditableSection 读取可执行文件中.dep-v0段的内容
+func readAuditableSection(r io.ReaderAt) []byte {
+
+	if f, err := elf.NewFile(r); err == nil {
+		if s := f.Section(auditableSection); s != nil {
+			data, _ := s.Data()
+			return data
+		}
+		return nil
+	}
+
+	if f, err := pe.NewFile(r); err == nil {
+		if s := f.Section(auditableSection); s != nil {
+			data, _ := s.Data()
+			// PE段按文件对齐填充
+			if s.VirtualSize > 0 && int(s.VirtualSize) < len(data) {
+				data = data[:s.VirtualSize]
+			}
+			return data
+		}
+		return nil
+	}
+
+	if f, err := macho.NewFile(r); err == nil {
+		// __DATA,.dep-v0
+		if s := f.Section(auditableSection); s != nil {
+			data, _ := s.Data()
+			return data
+		}
+	}
+
+	return nil
+}
@@ -11,12 +11,14 @@ import (
 )
 
 // ParseCargoLock 解析Cargo.lock文件
-func ParseCargoLock(file *model.File) *model.DepGraph {
+// members: workspace中的crate 用于确定项目crate及依赖的作用域 为nil时没有父依赖的crate作为项目crate
+func ParseCargoLock(file *model.File, members []*CargoToml) *model.DepGraph {
 
 	cargo := struct {
 		Pkgs []struct {
 			Name         string   `toml:"name"`
 			Version      string   `toml:"version"`
+			Source       string   `toml:"source"`
 			Dependencies []string `toml:"dependencies"`
 		} `toml:"package"`
 	}{}
@@ -36,30 +38,94 @@ func ParseCargoLock(file *model.File) *model.DepGraph {
 			Version: s[1],
 		}
 	}).LoadOrStore
+	// 本地crate key:crate名称
+	localMap := map[string]*model.DepGraph{}
 	for _, c := range cargo.Pkgs {
-		depMap[c.Name] = _dep(c.Name, c.Version)
+		dep := _dep(c.Name, c.Version)
+		depMap[c.Name] = dep
+		if c.Source == "" {
+			localMap[c.Name] = dep
+		}
 	}
 
 	// 记录依赖关系
+	// dependency: name | name version | name version (source)
 	for _, c := range cargo.Pkgs {
 		dep := _dep(c.Name, c.Version)
 		for _, dependency := range c.Dependencies {
-			i := strings.Index(dependency, " ")
-			if i != -1 {
-				name, version := dependency[:i], dependency[i+1:]
-				dep.AppendChild(_dep(name, version))
-			} else {
-				dep.AppendChild(depMap[dependency])
+			words := strings.Fields(dependency)
+			if len(words) > 1 {
+				dep.AppendChild(_dep(words[0], words[1]))
+			} else if len(words) == 1 {
+				dep.AppendChild(depMap[words[0]])
 			}
 		}
 	}
 
 	root := &model.DepGraph{Path: file.Relpath()}
-	for _, dep := range depMap {
-		if len(dep.Parents) == 0 {
+
+	// 项目crate及其直接依赖的作用域
+	memberSet := map[*model.DepGraph]bool{}
+	for _, m := range members {
+		member, ok := localMap[m.Name()]
+		if !ok {
+			continue
+		}
+		memberSet[member] = true
+		root.AppendChild(member)
+
+		scopes := map[string]model.Scope{}
+		for _, req := range m.Requires() {
+			scopes[req.Name] = scopes[req.Name].Merge(req.Scope)
+		}
+		for _, c := range member.Children {
+			if scope, ok := scopes[c.Name]; ok {
+				c.Scope = c.Scope.Merge(scope)
+			} else {
+				c.Scope = c.Scope.Merge(model.Scope_Compile)
+			}
+		}
+	}
+
+	// 没有父依赖的crate
+	for _, c := range cargo.Pkgs {
+		if dep := _dep(c.Name, c.Version); len(dep.Parents) == 0 && !memberSet[dep] {
 			root.AppendChild(dep)
 		}
 	}
 
+	if len(memberSet) == 0 {
+		return root
+	}
+
+	// 间接依赖继承父组件的作用域 仅被dev-dependencies及build-dependencies引入的组件标记为开发组件
+	queue := []*model.DepGraph{}
+	for member := range memberSet {
+		for _, c := range member.Children {
+			if !memberSet[c] {
+				queue = append(queue, c)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, c := range n.Children {
+			if memberSet[c] {
+				continue
+			}
+			if scope := c.Scope.Merge(n.Scope); scope != c.Scope {
+				c.Scope = scope
+				queue = append(queue, c)
+			}
+		}
+	}
+	root.ForEachNode(func(p, n *model.DepGraph) bool {
+		if p != nil && !memberSet[n] {
+			n.Develop = n.Scope.Develop()
+		}
+		return true
+	})
+
 	return root
 }
@@ -2,6 +2,8 @@ package rust
 
 import (
 	"context"
+	"path"
+	"strings"
 
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
@@ -14,16 +16,66 @@ func (sca Sca) Language() model.Language {
 }
 
 func (sca Sca) Filter(relpath string) bool {
-	return filter.RustCargoLock(relpath)
+	return filter.RustCargoLock(relpath) ||
+		filter.RustCargoToml(relpath) ||
+		filter.Executable(relpath)
 }
 
 func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
+
+	path2dir := func(relpath string) string { return path.Dir(strings.ReplaceAll(relpath, `\`, `/`)) }
+
+	// map[dir]
+	tomls := map[string]*CargoToml{}
+	locks := map[string]*model.File{}
 	for _, f := range files {
-		if filter.RustCargoLock(f.Relpath()) {
-			root := ParseCargoLock(f)
-			if root != nil && len(root.Children) > 0 {
-				call(f, root)
+		switch {
+		case filter.RustCargoLock(f.Relpath()):
+			locks[path2dir(f.Relpath())] = f
+		case filter.RustCargoToml(f.Relpath()):
+			if cargo := ReadCargoToml(f); cargo != nil {
+				tomls[path2dir(f.Relpath())] = cargo
+			}
+		case filter.Executable(f.Relpath()):
+			// 解析rust可执行文件
+			if bin := ParseRustBinary(f); bin != nil {
+				call(f, bin)
+			}
+		}
+	}
+
+	// workspace中的crate key:workspace目录
+	workspaces := map[string][]*CargoToml{}
+	memberSet := map[*CargoToml]bool{}
+	for dir, cargo := range tomls {
+		if cargo.Workspace != nil {
+			workspaces[dir] = cargo.members(tomls)
+			for _, m := range workspaces[dir] {
+				memberSet[m] = true
 			}
 		}
 	}
+	// 不属于workspace的crate
+	for dir, cargo := range tomls {
+		if cargo.Workspace == nil && !memberSet[cargo] {
+			workspaces[dir] = []*CargoToml{cargo}
+		}
+	}
+
+	for dir, members := range workspaces {
+		if lock, ok := locks[dir]; ok {
+			call(lock, ParseCargoLock(lock, members))
+			delete(locks, dir)
+		} else {
+			call(tomls[dir].File, ParseCargoToml(tomls[dir].File, members))
+		}
+	}
+
+	// 没有Cargo.toml的Cargo.lock
+	for _, f := range locks {
+		root := ParseCargoLock(f, nil)
+		if root != nil && len(root.Children) > 0 {
+			call(f, root)
+		}
+	}
 }
@@ -0,0 +1,258 @@
+package rust
+
+import (
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+
+	"github.com/BurntSushi/toml"
+)
+
+// cargoDependencies Cargo.toml中的依赖声明
+// value: "1.0" | {version = "1.0", path = "../x", package = "y", workspace = true, optional = true}
+type cargoDependencies struct {
+	Dependencies      map[string]any `toml:"dependencies"`
+	DevDependencies   map[string]any `toml:"dev-dependencies"`
+	BuildDependencies map[string]any `toml:"build-dependencies"`
+}
+
+// CargoToml Cargo.toml
+// https://doc.rust-lang.org/cargo/reference/manifest.html
+type CargoToml struct {
+	Package struct {
+		Name string `toml:"name"`
+		// "0.1.0" | {workspace = true}
+		Version any `toml:"version"`
+	} `toml:"package"`
+	Workspace *struct {
+		Members []string `toml:"members"`
+		Exclude []string `toml:"exclude"`
+		Package struct {
+			Version string `toml:"version"`
+		} `toml:"package"`
+		Dependencies map[string]any `toml:"dependencies"`
+	} `toml:"workspace"`
+	Dependencies      map[string]any `toml:"dependencies"`
+	DevDependencies   map[string]any `toml:"dev-dependencies"`
+	BuildDependencies map[string]any `toml:"build-dependencies"`
+	// 指定平台的依赖 key:cfg(windows)|x86_64-pc-windows-gnu
+	Target map[string]cargoDependencies `toml:"target"`
+	File   *model.File                  `toml:"-"`
+	// 所属的workspace 用于继承workspace中声明的版本及依赖
+	workspace *CargoToml
+}
+
+// cargoRequire 项目声明的直接依赖
+type cargoRequire struct {
+	// crate名称 使用package重命名时为实际名称
+	Name     string
+	Version  string
+	Path     string
+	Optional bool
+	// compile|test(dev-dependencies)|build(build-dependencies)
+	Scope model.Scope
+}
+
+// ReadCargoToml 读取Cargo.toml
+func ReadCargoToml(file *model.File) *CargoToml {
+	var cargo *CargoToml
+	file.OpenReader(func(reader io.Reader) {
+		cargo = &CargoToml{File: file}
+		if _, err := toml.NewDecoder(reader).Decode(cargo); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+			cargo = nil
+		}
+	})
+	return cargo
+}
+
+// dir Cargo.toml所在目录
+func (c *CargoToml) dir() string {
+	return path.Dir(strings.ReplaceAll(c.File.Relpath(), `\`, `/`))
+}
+
+// Name crate名称
+func (c *CargoToml) Name() string {
+	return c.Package.Name
+}
+
+// Version crate版本 version.workspace = true时使用workspace中的版本
+func (c *CargoToml) Version() string {
+	switch v := c.Package.Version.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if c.workspace != nil && v["workspace"] == true {
+			return c.workspace.Workspace.Package.Version
+		}
+	}
+	return ""
+}
+
+// members workspace中的crate 包含workspace根目录中的crate
+// tomls: 项目中的Cargo.toml key:所在目录
+func (c *CargoToml) members(tomls map[string]*CargoToml) []*CargoToml {
+
+	var members []*CargoToml
+	dir := c.dir()
+
+	if c.Package.Name != "" {
+		c.workspace = c
+		members = append(members, c)
+	}
+
+	match := func(patterns []string, member string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(path.Join(dir, pattern), member); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	dirs := make([]string, 0, len(tomls))
+	for d := range tomls {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+
+	for _, d := range dirs {
+		member := tomls[d]
+		if member == c || member.Package.Name == "" {
+			continue
+		}
+		if match(c.Workspace.Members, d) && !match(c.Workspace.Exclude, d) {
+			member.workspace = c
+			members = append(members, member)
+		}
+	}
+
+	return members
+}
+
+// Requires 项目声明的直接依赖 包括指定平台的依赖
+func (c *CargoToml) Requires() []cargoRequire {
+
+	var requires []cargoRequire
+
+	add := func(deps map[string]any, scope model.Scope) {
+		keys := make([]string, 0, len(deps))
+		for k := range deps {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			req := c.require(key, deps[key])
+			req.Scope = scope
+			requires = append(requires, req)
+		}
+	}
+
+	targets := make([]string, 0, len(c.Target))
+	for k := range c.Target {
+		targets = append(targets, k)
+	}
+	sort.Strings(targets)
+
+	add(c.Dependencies, model.Scope_Compile)
+	for _, t := range targets {
+		add(c.Target[t].Dependencies, model.Scope_Compile)
+	}
+	add(c.DevDependencies, model.Scope_Test)
+	for _, t := range targets {
+		add(c.Target[t].DevDependencies, model.Scope_Test)
+	}
+	add(c.BuildDependencies, model.Scope_Build)
+	for _, t := range targets {
+		add(c.Target[t].BuildDependencies, model.Scope_Build)
+	}
+
+	return requires
+}
+
+// require 解析依赖声明 workspace = true时继承workspace.dependencies中的声明
+func (c *CargoToml) require(key string, value any) cargoRequire {
+
+	req := cargoRequire{Name: key}
+
+	spec := map[string]any{}
+	base := c.dir()
+	switch v := value.(type) {
+	case string:
+		req.Version = v
+		return req
+	case map[string]any:
+		if v["workspace"] == true && c.workspace != nil && c.workspace.Workspace != nil {
+			// workspace中的路径相对于workspace根目录
+			base = c.workspace.dir()
+			switch w := c.workspace.Workspace.Dependencies[key].(type) {
+			case string:
+				spec["version"] = w
+			case map[string]any:
+				for k, v := range w {
+					spec[k] = v
+				}
+			}
+		}
+		for k, v := range v {
+			spec[k] = v
+		}
+	}
+
+	if name, ok := spec["package"].(string); ok && name != "" {
+		req.Name = name
+	}
+	if p, ok := spec["path"].(string); ok && p != "" {
+		req.Path = path.Join(base, p)
+	}
+	req.Version, _ = spec["version"].(string)
+	req.Optional, _ = spec["optional"].(bool)
+
+	return req
+}
+
+// ParseCargoToml 解析Cargo.toml中声明的直接依赖 用于没有Cargo.lock的项目
+// members: workspace中的crate 非workspace项目为crate自身
+func ParseCargoToml(file *model.File, members []*CargoToml) *model.DepGraph {
+
+	root := &model.DepGraph{Path: file.Relpath()}
+
+	// key:crate所在目录
+	nodes := map[string]*model.DepGraph{}
+	for _, m := range members {
+		node := &model.DepGraph{Name: m.Name(), Version: m.Version(), Path: m.File.Relpath()}
+		nodes[m.dir()] = node
+		root.AppendChild(node)
+	}
+
+	for _, m := range members {
+		node := nodes[m.dir()]
+		_dep := model.NewDepGraphMap(nil, func(s ...string) *model.DepGraph {
+			return &model.DepGraph{Name: s[0], Version: s[1]}
+		}).LoadOrStore
+		for _, req := range m.Requires() {
+			// 依赖workspace中的其他crate
+			if member, ok := nodes[req.Path]; ok && req.Path != "" {
+				node.AppendChild(member)
+				continue
+			}
+			dep := _dep(req.Name, req.Version)
+			dep.Local = dep.Local || req.Path != ""
+			// 同一依赖多次声明时任一声明为必需即为必需
+			if dep.Scope == model.Scope_None {
+				dep.Optional = req.Optional
+			} else {
+				dep.Optional = dep.Optional && req.Optional
+			}
+			dep.Scope = dep.Scope.Merge(req.Scope)
+			dep.Develop = dep.Scope.Develop()
+			node.AppendChild(dep)
+		}
+	}
+
+	return root
+}
@@ -0,0 +1,35 @@
+package sbt
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// SbtLock build.sbt.lock中锁定的组件
+// https://github.com/stringbean/sbt-dependency-lock
+type SbtLock struct {
+	Org            string   `json:"org"`
+	Name           string   `json:"name"`
+	Version        string   `json:"version"`
+	Configurations []string `json:"configurations"`
+}
+
+// ParseSbtLock 解析sbt-dependency-lock生成的锁文件
+func ParseSbtLock(file *model.File) []*SbtLock {
+
+	lock := struct {
+		LockVersion  int        `json:"lockVersion"`
+		Dependencies []*SbtLock `json:"dependencies"`
+	}{}
+
+	file.OpenReader(func(reader io.Reader) {
+		if err := json.NewDecoder(reader).Decode(&lock); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	return lock.Dependencies
+}
@@ -0,0 +1,318 @@
+package sbt
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/java"
+)
+
+// sbtDecl sbt脚本中声明的依赖
+type sbtDecl struct {
+	Org     string
+	Name    string
+	Version string
+	// 是否需要追加scala版本后缀 %%
+	Cross bool
+	// 依赖配置 Test/"provided"/"test->test"
+	Conf string
+	// 是否为构建定义(project/*.sbt)中的依赖
+	Plugin bool
+	// 是否为addSbtPlugin声明的sbt插件
+	SbtPlugin  bool
+	Exclusions []*java.PomDependency
+}
+
+// SbtBuild 一个sbt构建(根目录的*.sbt及project/*.sbt)
+type SbtBuild struct {
+	// build.sbt 没有build.sbt时为根目录的其他sbt脚本
+	File    *model.File
+	Org     string
+	Name    string
+	Version string
+	// scala版本
+	ScalaVersion string
+	// project/build.properties中的sbt版本
+	SbtVersion string
+	Resolvers  []string
+	deps       []*sbtDecl
+}
+
+var (
+	// val akkaVersion = "2.6.20" | lazy val scala213: String = "2.13.12"
+	valReg = regexp.MustCompile(`^\s*(?:lazy\s+)?val\s+(\w+)\s*(?::\s*String\s*)?=\s*"([^"]*)"\s*$`)
+	// ThisBuild / scalaVersion := "2.13.12" | version in ThisBuild := "1.0"
+	settingReg = regexp.MustCompile(`(?:^|[\s/(])(organization|name|version|scalaVersion)(?:\s+in\s+\w+)?\s*:=\s*("[^"]*"|[\w.]+)`)
+	// "org" %% "name" % "version" % Test
+	moduleReg = regexp.MustCompile(`"([^"\s]+)"\s*(%%%|%%|%)\s*"([^"\s]+)"\s*%\s*("[^"]*"|[\w.]+)(?:\s*%\s*("[^"]*"|[\w.]+))?`)
+	// addSbtPlugin("org" % "name" % "version")
+	pluginReg = regexp.MustCompile(`addSbtPlugin\s*\(`)
+	// .exclude("org", "name")
+	excludeReg = regexp.MustCompile(`exclude\s*\(\s*"([^"]+)"\s*,\s*"([^"]+)"\s*\)`)
+	// resolvers += "name" at "url"
+	resolverReg = regexp.MustCompile(`"[^"]*"\s+at\s+"(https?://[^"]+)"`)
+)
+
+// scanVariables 记录sbt脚本中定义的字符串变量
+func scanVariables(file *model.File, vars map[string]string) {
+	file.ReadLineNoComment(model.CTypeComment, func(line string) {
+		if m := valReg.FindStringSubmatch(line); len(m) == 3 {
+			vars[m[1]] = m[2]
+		}
+	})
+}
+
+// ReadSbtVersion 读取project/build.properties中的sbt.version
+func ReadSbtVersion(file *model.File) (version string) {
+	file.ReadLineNoComment(model.PythonTypeComment, func(line string) {
+		if k, v, ok := strings.Cut(line, "="); ok && strings.TrimSpace(k) == "sbt.version" {
+			version = strings.TrimSpace(v)
+		}
+	})
+	return
+}
+
+// sbtCrossVersion sbt版本对应的构建定义scala二进制版本及插件后缀
+// sbt0.13 => 2.10 _2.10_0.13 | sbt1.x => 2.12 _2.12_1.0 | sbt2.x => 3 _sbt2_3
+func sbtCrossVersion(sbtVersion string) (scala, plugin string) {
+	switch {
+	case strings.HasPrefix(sbtVersion, "0.13"):
+		return "2.10", "_2.10_0.13"
+	case strings.HasPrefix(sbtVersion, "2."):
+		return "3", "_sbt2_3"
+	default:
+		// 未指定时按sbt1.x处理
+		return "2.12", "_2.12_1.0"
+	}
+}
+
+// parseSbtFile 解析sbt脚本
+// plugin: 是否为构建定义中的脚本(project/*.sbt)
+func parseSbtFile(build *SbtBuild, file *model.File, vars map[string]string, plugin bool) {
+
+	// 字符串字面量或变量
+	value := func(s string) string {
+		if strings.HasPrefix(s, `"`) {
+			return strings.Trim(s, `"`)
+		}
+		if v, ok := vars[s]; ok {
+			return v
+		}
+		// Versions.akka 取最后一段变量名
+		if i := strings.LastIndex(s, "."); i != -1 {
+			if v, ok := vars[s[i+1:]]; ok {
+				return v
+			}
+		}
+		return ""
+	}
+
+	file.ReadLineNoComment(model.CTypeComment, func(line string) {
+
+		if !plugin {
+			for _, m := range settingReg.FindAllStringSubmatch(line, -1) {
+				v := value(m[2])
+				switch m[1] {
+				case "organization":
+					if build.Org == "" {
+						build.Org = v
+					}
+				case "name":
+					if build.Name == "" {
+						build.Name = v
+					}
+				case "version":
+					if build.Version == "" {
+						build.Version = v
+					}
+				case "scalaVersion":
+					if build.ScalaVersion == "" {
+						build.ScalaVersion = v
+					}
+				}
+			}
+		}
+
+		for _, m := range resolverReg.FindAllStringSubmatch(line, -1) {
+			build.Resolvers = append(build.Resolvers, strings.TrimSuffix(m[1], "/"))
+		}
+
+		for _, m := range moduleReg.FindAllStringSubmatch(line, -1) {
+			dep := &sbtDecl{
+				Org:     m[1],
+				Name:    m[3],
+				Version: value(m[4]),
+				Cross:   m[2] != "%",
+				Conf:    value(m[5]),
+				Plugin:  plugin,
+			}
+			// % Test 等配置对象
+			if dep.Conf == "" && m[5] != "" && !strings.HasPrefix(m[5], `"`) {
+				dep.Conf = m[5]
+			}
+			// 构建插件 后缀取决于sbt版本
+			if pluginReg.MatchString(line) {
+				dep.Plugin = true
+				dep.SbtPlugin = true
+				dep.Cross = false
+			}
+			if strings.Contains(line, "intransitive()") {
+				dep.Exclusions = append(dep.Exclusions, &java.PomDependency{GroupId: "*", ArtifactId: "*"})
+			}
+			for _, e := range excludeReg.FindAllStringSubmatch(line, -1) {
+				dep.Exclusions = append(dep.Exclusions, &java.PomDependency{GroupId: e[1], ArtifactId: e[2]})
+			}
+			build.deps = append(build.deps, dep)
+		}
+	})
+}
+
+// scalaBinaryVersion scala二进制版本 2.13.12 => 2.13 3.3.1 => 3
+func scalaBinaryVersion(version string) string {
+	if version == "" {
+		// sbt1.x默认scala版本
+		return "2.12"
+	}
+	vs := strings.Split(version, ".")
+	if vs[0] == "3" || len(vs) < 2 {
+		return vs[0]
+	}
+	return vs[0] + "." + vs[1]
+}
+
+// sbtConfs 获取依赖配置 "compile->compile;test->test" => [compile test]
+func sbtConfs(conf string) []string {
+	var confs []string
+	for _, mapping := range strings.Split(conf, ";") {
+		if i := strings.Index(mapping, "->"); i != -1 {
+			mapping = mapping[:i]
+		}
+		for _, c := range strings.Split(mapping, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				confs = append(confs, c)
+			}
+		}
+	}
+	return confs
+}
+
+// sbtScope 将sbt依赖配置映射为组件作用域
+func sbtScope(confs []string) model.Scope {
+	scope := model.Scope_None
+	for _, c := range confs {
+		c = strings.ToLower(c)
+		switch {
+		case strings.Contains(c, "test") || c == "it" || c == "integrationtest":
+			scope = scope.Merge(model.Scope_Test)
+		case strings.Contains(c, "runtime"):
+			scope = scope.Merge(model.Scope_Runtime)
+		case strings.HasPrefix(c, "scala-tool") || strings.HasPrefix(c, "plugin"):
+			scope = scope.Merge(model.Scope_Build)
+		default:
+			scope = scope.Merge(model.Scope_Compile)
+		}
+	}
+	if scope == model.Scope_None {
+		scope = model.Scope_Compile
+	}
+	return scope
+}
+
+// ParseSbt 解析sbt构建
+// lock: build.sbt.lock中锁定的依赖 为空时借助java模块解析间接依赖
+func ParseSbt(ctx context.Context, build *SbtBuild, lock []*SbtLock) *model.DepGraph {
+
+	pom := &java.Pom{File: build.File, Repositories: build.Resolvers}
+	pom.GroupId = build.Org
+	pom.ArtifactId = build.Name
+	pom.Version = build.Version
+
+	binary := scalaBinaryVersion(build.ScalaVersion)
+	// 构建定义使用sbt自身的scala版本
+	metaBinary, pluginSuffix := sbtCrossVersion(build.SbtVersion)
+
+	// 记录直接依赖的作用域 map[org:name]
+	scopes := map[string]model.Scope{}
+
+	// sbt默认引入scala标准库
+	if build.ScalaVersion != "" && len(lock) == 0 {
+		lib := &sbtDecl{Org: "org.scala-lang", Name: "scala-library", Version: build.ScalaVersion}
+		if binary == "3" {
+			lib.Name = "scala3-library"
+			lib.Cross = true
+		}
+		build.deps = append([]*sbtDecl{lib}, build.deps...)
+	}
+
+	for _, d := range build.deps {
+
+		// 存在锁文件时以锁文件为准
+		if !d.Plugin && len(lock) > 0 {
+			continue
+		}
+
+		dep := &java.PomDependency{GroupId: d.Org, ArtifactId: d.Name, Version: d.Version, Exclusions: d.Exclusions}
+		if d.SbtPlugin {
+			dep.ArtifactId += pluginSuffix
+		} else if d.Cross && d.Plugin {
+			dep.ArtifactId += "_" + metaBinary
+		} else if d.Cross {
+			dep.ArtifactId += "_" + binary
+		}
+
+		if d.Plugin {
+			pom.Plugins = append(pom.Plugins, dep)
+			continue
+		}
+
+		scope := sbtScope(sbtConfs(d.Conf))
+		if scope.Develop() {
+			dep.Scope = "test"
+		}
+		key := dep.GroupId + ":" + dep.ArtifactId
+		scopes[key] = scopes[key].Merge(scope)
+		pom.Dependencies = append(pom.Dependencies, dep)
+	}
+
+	root := &model.DepGraph{Vendor: pom.GroupId, Name: pom.ArtifactId, Version: pom.Version, Path: build.File.Relpath()}
+	java.ParsePoms(ctx, []*java.Pom{pom}, nil, func(pom *java.Pom, pomResult *model.DepGraph) {
+		pomResult.ForEachNode(func(p, n *model.DepGraph) bool {
+			if p == nil {
+				return true
+			}
+			if s, ok := scopes[n.Vendor+":"+n.Name]; ok && p == pomResult {
+				n.Scope = s
+			} else if n.Scope == model.Scope_None {
+				n.Scope = p.Scope
+			}
+			return true
+		})
+		root = pomResult
+	})
+
+	// 添加锁文件中的组件
+	for _, l := range lock {
+		scope := sbtScope(l.Configurations)
+		root.AppendChild(&model.DepGraph{
+			Vendor:  l.Org,
+			Name:    l.Name,
+			Version: l.Version,
+			Scope:   scope,
+			Develop: scope.Develop(),
+		})
+	}
+
+	return root
+}
+
+// buildDir sbt脚本所属的构建目录 project/*.sbt属于上级目录的构建
+func buildDir(relpath string) (dir string, plugin bool) {
+	dir = filepath.Dir(relpath)
+	if filepath.Base(dir) == "project" {
+		return filepath.Dir(dir), true
+	}
+	return dir, false
+}
@@ -0,0 +1,91 @@
+package sbt
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+type Sca struct{}
+
+func (sca Sca) Language() model.Language {
+	return model.Lan_Java
+}
+
+func (sca Sca) Filter(relpath string) bool {
+	return filter.JavaSbt(relpath) || filter.JavaSbtLock(relpath) || filter.JavaSbtProperties(relpath)
+}
+
+func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
+
+	// map[dir]
+	builds := map[string]*SbtBuild{}
+	locks := map[string][]*SbtLock{}
+	// 根目录中build.sbt以外的sbt脚本 map[dir]
+	sources := map[string][]*model.File{}
+	// 构建定义中的脚本 map[dir]
+	metas := map[string][]*model.File{}
+	// sbt版本 map[dir]
+	sbtVersions := map[string]string{}
+
+	// 变量在构建定义及构建脚本间共享
+	vars := map[string]string{}
+
+	for _, f := range files {
+		rel := f.Relpath()
+		if filter.JavaSbtLock(rel) {
+			locks[filepath.Dir(rel)] = ParseSbtLock(f)
+			continue
+		}
+		if filter.JavaSbtProperties(rel) {
+			dir, _ := buildDir(rel)
+			sbtVersions[dir] = ReadSbtVersion(f)
+			continue
+		}
+		if !filter.JavaSbt(rel) {
+			continue
+		}
+		scanVariables(f, vars)
+		dir, plugin := buildDir(rel)
+		if plugin {
+			metas[dir] = append(metas[dir], f)
+		} else if filepath.Base(rel) == "build.sbt" {
+			builds[dir] = &SbtBuild{File: f}
+		} else {
+			sources[dir] = append(sources[dir], f)
+		}
+	}
+
+	// 没有build.sbt的构建使用根目录的其他sbt脚本
+	for dir, fs := range sources {
+		sort.Slice(fs, func(i, j int) bool { return fs[i].Relpath() < fs[j].Relpath() })
+		if _, ok := builds[dir]; !ok {
+			builds[dir] = &SbtBuild{File: fs[0]}
+			sources[dir] = fs[1:]
+		}
+	}
+
+	dirs := []string{}
+	for dir := range builds {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		build := builds[dir]
+		build.SbtVersion = sbtVersions[dir]
+		parseSbtFile(build, build.File, vars, false)
+		for _, f := range sources[dir] {
+			parseSbtFile(build, f, vars, false)
+		}
+		for _, f := range metas[dir] {
+			parseSbtFile(build, f, vars, true)
+		}
+		if root := ParseSbt(ctx, build, locks[dir]); root != nil {
+			call(build.File, root)
+		}
+	}
+}
@@ -4,16 +4,24 @@ import (
 	"context"
 
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/cocoapods"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/dart"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/dotnet"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/elixir"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/erlang"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/golang"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/groovy"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/ivy"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/java"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/javascript"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/jslib"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/php"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/python"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/ruby"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/rust"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/sbom"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/sbt"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/swift"
 )
 
 type Sca interface {
@@ -25,12 +33,20 @@ type Sca interface {
 var AllSca = []Sca{
 	python.Sca{},
 	javascript.Sca{},
+	jslib.Sca{},
 	golang.Sca{},
 	ruby.Sca{},
 	rust.Sca{},
 	erlang.Sca{},
+	elixir.Sca{},
 	php.Sca{},
+	dotnet.Sca{},
+	swift.Sca{},
+	cocoapods.Sca{},
+	dart.Sca{},
 	java.Sca{},
 	groovy.Sca{},
+	ivy.Sca{},
+	sbt.Sca{},
 	sbom.Sca{},
 }
@@ -0,0 +1,122 @@
+package swift
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/logs"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+)
+
+// packagePin Package.resolved中锁定的组件
+type packagePin struct {
+	// v2/v3 组件标识 registry组件为scope.name
+	Identity string `json:"identity"`
+	// v2/v3 remoteSourceControl|localSourceControl|registry
+	Kind string `json:"kind"`
+	// v2/v3 仓库地址
+	Location string `json:"location"`
+	// v1 组件名
+	Package string `json:"package"`
+	// v1 仓库地址
+	RepositoryURL string `json:"repositoryURL"`
+	State         struct {
+		Branch   string `json:"branch"`
+		Revision string `json:"revision"`
+		Version  string `json:"version"`
+	} `json:"state"`
+}
+
+// packageResolved Package.resolved
+// v1: {"object": {"pins": [...]}, "version": 1}
+// v2/v3: {"pins": [...], "version": 2}
+type packageResolved struct {
+	Version int          `json:"version"`
+	Pins    []packagePin `json:"pins"`
+	Object  struct {
+		Pins []packagePin `json:"pins"`
+	} `json:"object"`
+}
+
+// splitLocation 将仓库地址拆分为namespace及组件名
+// https://github.com/apple/swift-nio.git => github.com/apple swift-nio
+// git@github.com:apple/swift-nio.git => github.com/apple swift-nio
+func splitLocation(location string) (namespace, name string) {
+	location = strings.TrimSpace(location)
+	if u, err := url.Parse(location); err == nil && u.Host != "" {
+		location = u.Host + u.Path
+	} else if i := strings.Index(location, "@"); i != -1 && strings.Contains(location[i:], ":") {
+		// scp形式的ssh地址
+		location = strings.Replace(location[i+1:], ":", "/", 1)
+	}
+	location = strings.TrimSuffix(strings.TrimSuffix(location, "/"), ".git")
+	namespace, name = path.Split(location)
+	return strings.TrimSuffix(namespace, "/"), name
+}
+
+// ParsePackageResolved 解析Package.resolved
+// Package.resolved中仅记录锁定的组件 不包含依赖关系
+func ParsePackageResolved(file *model.File) *model.DepGraph {
+
+	resolved := &packageResolved{}
+	file.OpenReader(func(reader io.Reader) {
+		if err := json.NewDecoder(reader).Decode(resolved); err != nil {
+			logs.Warnf("parse %s fail:%s", file.Relpath(), err)
+		}
+	})
+
+	pins := resolved.Pins
+	if resolved.Version <= 1 {
+		pins = resolved.Object.Pins
+	}
+
+	root := &model.DepGraph{Path: file.Relpath()}
+	for _, pin := range pins {
+
+		dep := &model.DepGraph{
+			Version: pin.State.Version,
+			// 锁定的提交
+			Revision: pin.State.Revision,
+		}
+		if dep.Version == "" {
+			// 通过分支或提交引用的组件
+			dep.Version = pin.State.Branch
+		}
+		if dep.Version == "" {
+			dep.Version = pin.State.Revision
+		}
+
+		location := pin.Location
+		if location == "" {
+			location = pin.RepositoryURL
+		}
+		dep.Source = location
+
+		switch pin.Kind {
+		case "registry":
+			// registry组件标识为scope.name
+			dep.Vendor, dep.Name, _ = strings.Cut(pin.Identity, ".")
+			if dep.Name == "" {
+				dep.Vendor, dep.Name = "", dep.Vendor
+			}
+		case "localSourceControl":
+			dep.Name = pin.Identity
+			dep.Local = true
+		default:
+			dep.Vendor, dep.Name = splitLocation(location)
+			if dep.Name == "" {
+				dep.Name = pin.Identity
+			}
+		}
+		if dep.Name == "" {
+			dep.Name = pin.Package
+		}
+
+		root.AppendChild(dep)
+	}
+
+	return root
+}
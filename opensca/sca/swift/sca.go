@@ -0,0 +1,26 @@
+package swift
+
+import (
+	"context"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+)
+
+type Sca struct{}
+
+func (sca Sca) Language() model.Language {
+	return model.Lan_Swift
+}
+
+func (sca Sca) Filter(relpath string) bool {
+	return filter.SwiftPackageResolved(relpath)
+}
+
+func (sca Sca) Sca(ctx context.Context, parent *model.File, files []*model.File, call model.ResCallback) {
+	for _, f := range files {
+		if sca.Filter(f.Relpath()) {
+			call(f, ParsePackageResolved(f))
+		}
+	}
+}
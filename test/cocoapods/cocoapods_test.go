@@ -0,0 +1,45 @@
+package cocoapods
+
+import (
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/cocoapods"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_CocoaPods(t *testing.T) {
+
+	alamofire := tool.Dep("Alamofire", "5.8.1")
+
+	tool.RunTaskCase(t, cocoapods.Sca{})([]tool.TaskCase{
+
+		// Podfile.lock
+		{Path: "1", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				alamofire,
+				tool.Dep("Firebase", "10.18.0",
+					tool.Dep("FirebaseAnalytics", "10.18.0",
+						tool.Dep("GoogleUtilities", "7.12.0"),
+						tool.Dep("nanopb", "2.30909.1"),
+					),
+				),
+				tool.Dep("Kingfisher", "7.10.1"),
+				tool.Dep("MyLib", "0.1.0", alamofire),
+				tool.Dep("SwiftLint", "0.54.0"),
+			),
+		)},
+	})
+
+	// 本地组件及完整性校验值
+	tool.RunAttrCase(t, tool.Integrity, cocoapods.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{
+			"Alamofire": "3ca42e259043ee0dc5c0cdd76c4bc568b8e42af7",
+			"MyLib":     "local",
+		}},
+	})
+
+	if purl := model.Purl("", "Alamofire", "5.8.1", model.Lan_CocoaPods); purl != "pkg:cocoapods/Alamofire@5.8.1" {
+		t.Errorf("purl:%s", purl)
+	}
+}
@@ -0,0 +1,80 @@
+package dart
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/dart"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_Dart(t *testing.T) {
+	tool.RunTaskCase(t, dart.Sca{})([]tool.TaskCase{
+
+		// pubspec.lock + pubspec.yaml
+		{Path: "1", Result: tool.Dep("", "",
+			tool.Dep("demo_app", "1.2.0+3",
+				tool.Dep("dart", ">=3.0.0 <4.0.0"),
+				tool.Dep("flutter", ">=3.10.0"),
+				tool.Dep("collection", "1.17.2"),
+				tool.DevDep("flutter_lints", "3.0.1"),
+				tool.Dep("http", "1.1.0"),
+				tool.Dep("markdown_widget", "2.3.2"),
+				tool.Dep("meta", "1.10.0"),
+				tool.Dep("provider", "6.0.5"),
+				tool.Dep("shared_utils", "0.1.0"),
+			),
+		)},
+
+		// 仅有pubspec.yaml
+		{Path: "2", Result: tool.Dep("", "",
+			tool.Dep("cli_tool", "0.3.0",
+				tool.Dep("dart", "^3.2.0"),
+				tool.Dep("args", "^2.4.2"),
+				tool.DevDep("lints", "^3.0.0"),
+				tool.Dep("local_core", ""),
+				tool.Dep("path", ""),
+				tool.DevDep("test", "^1.24.0"),
+				tool.Dep("yaml", "3.1.1"),
+			),
+		)},
+
+		// 仅有pubspec.lock
+		{Path: "3", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("dart", ">=3.0.0 <4.0.0"),
+				tool.Dep("async", "2.11.0"),
+				tool.DevDep("lints", "3.0.0"),
+			),
+		)},
+	})
+
+	// 组件来源
+	tool.RunAttrCase(t, tool.Integrity, dart.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{
+			"http":            "759d1a329847dd0f39226c688d3e06a6b8679668e350e2891a6474f8b4bb8525",
+			"markdown_widget": "",
+			"shared_utils":    "local",
+		}},
+	})
+	tool.RunAttrCase(t, tool.Source, dart.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{
+			"markdown_widget": "https://github.com/asjqkkkk/markdown_widget.git#6c0c8f0f9a3b8e3f8f1e7a0d4b5c6e7f8091a2b3",
+			"http":            "",
+		}},
+	})
+	tool.RunAttrCase(t, func(n *model.DepGraph) string { return n.Override }, dart.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{"meta": "1.10.0", "http": ""}},
+	})
+
+	// 间接依赖不是直接依赖
+	tool.RunAttrCase(t, func(n *model.DepGraph) string { return strconv.FormatBool(n.Direct) }, dart.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{"collection": "false", "http": "true", "flutter_lints": "true", "meta": "true"}},
+		{Path: "3", Want: map[string]string{"async": "false", "lints": "true"}},
+	})
+
+	if purl := model.Purl("", "http", "1.1.0", model.Lan_Dart); purl != "pkg:pub/http@1.1.0" {
+		t.Errorf("purl:%s", purl)
+	}
+}
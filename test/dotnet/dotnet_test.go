@@ -0,0 +1,87 @@
+package dotnet
+
+import (
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/dotnet"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_DotNet(t *testing.T) {
+
+	json := tool.Dep("Newtonsoft.Json", "13.0.3")
+
+	tool.RunTaskCase(t, dotnet.Sca{})([]tool.TaskCase{
+
+		// csproj + obj/project.assets.json
+		{Path: "1", Result: tool.Dep("", "",
+			tool.Dep("App", "1.2.0",
+				tool.Dep("dotnet", "6.0"),
+				tool.Dep("Lib", "1.0.0", json),
+				json,
+				tool.Dep("Serilog", "3.1.1"),
+				tool.DevDep("StyleCop.Analyzers", "1.1.118",
+					tool.DevDep("StyleCop.Analyzers.Unstable", "1.2.0.507"),
+				),
+			),
+		)},
+
+		// csproj + packages.lock.json 多目标框架
+		{Path: "2", Result: tool.Dep("", "",
+			tool.Dep("Web", "",
+				tool.Dep("dotnet", "8.0"),
+				tool.Dep("dotnet-framework", "4.8"),
+				tool.DevDep("Microsoft.SourceLink.GitHub", "8.0.0",
+					tool.DevDep("Microsoft.Build.Tasks.Git", "8.0.0"),
+					tool.DevDep("Microsoft.SourceLink.Common", "8.0.0"),
+				),
+				tool.Dep("Serilog", "3.1.1",
+					tool.Dep("System.Diagnostics.DiagnosticSource", "7.0.2"),
+				),
+			),
+		)},
+
+		// Directory.Packages.props 集中管理版本
+		{Path: "3", Result: tool.Dep("", "",
+			tool.Dep("Demo.App", "0.4.0",
+				tool.Dep("Dapper", "2.1.24"),
+				tool.Dep("FSharp.Core", "8.0.101"),
+				tool.DevDep("Nerdbank.GitVersioning", "3.6.133"),
+				tool.DevDep("xunit", "2.6.2"),
+			),
+		)},
+
+		// packages.config
+		{Path: "4", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("dotnet-framework", "4.7.2"),
+				tool.Dep("EntityFramework", "6.4.4"),
+				tool.Dep("log4net", "2.0.15"),
+				tool.DevDep("NUnit", "3.14.0"),
+			),
+		)},
+	})
+
+	// 本地组件及完整性校验值
+	tool.RunAttrCase(t, tool.Integrity, dotnet.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{
+			"Lib":     "local",
+			"Serilog": "P6G4/4Kt9bT635bhuwdXlJ2SCqqn2nhh4gqFqQueCOr9bK/e7W9ll/IoX1Ter948cV2Z/5+5v8pAfJYUISY03A==",
+		}},
+	})
+	if purl := model.Purl("", "Serilog", "3.1.1", model.Lan_DotNet); purl != "pkg:nuget/Serilog@3.1.1" {
+		t.Errorf("purl:%s", purl)
+	}
+
+	// 运行时组件
+	if purl := model.Purl("", model.Runtime_DotNet, "8.0", model.Lan_Runtime); purl != "pkg:nuget/Microsoft.NETCore.App@8.0" {
+		t.Errorf("purl:%s", purl)
+	}
+	if purl := model.Purl("", model.Runtime_DotNetFramework, "4.8", model.Lan_Runtime); purl != "pkg:generic/dotnet-framework@4.8?ecosystem=nuget" {
+		t.Errorf("purl:%s", purl)
+	}
+	if _, name, _, language := model.ParsePurl("pkg:generic/dotnet-framework@4.8?ecosystem=nuget"); name != model.Runtime_DotNetFramework || language != model.Lan_Runtime {
+		t.Errorf("parse purl name:%s language:%s", name, language)
+	}
+}
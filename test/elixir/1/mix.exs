@@ -0,0 +1,25 @@
+defmodule Demo.MixProject do
+  use Mix.Project
+
+  @version "0.3.0"
+
+  def project do
+    [
+      app: :demo,
+      version: @version,
+      elixir: "~> 1.15",
+      deps: deps()
+    ]
+  end
+
+  defp deps do
+    [
+      {:phoenix, "~> 1.7.0"},
+      {:jason, "~> 1.4"},
+      {:plug_cowboy, git: "https://github.com/elixir-plug/plug_cowboy.git", tag: "v2.6.1"},
+      # 仅用于开发及测试
+      {:credo, "~> 1.7", only: [:dev, :test], runtime: false},
+      {:local_lib, path: "../local_lib"}
+    ]
+  end
+end
@@ -0,0 +1,20 @@
+defmodule Web.MixProject do
+  use Mix.Project
+
+  def project do
+    [
+      app: :web,
+      version: "0.2.0",
+      build_path: "../../_build",
+      lockfile: "../../mix.lock",
+      deps: deps()
+    ]
+  end
+
+  defp deps do
+    [
+      {:core, in_umbrella: true},
+      {:plug, "~> 1.15"}
+    ]
+  end
+end
@@ -0,0 +1,13 @@
+defmodule Plug.MixProject do
+  use Mix.Project
+
+  def project do
+    [app: :plug, version: "1.15.2", deps: deps()]
+  end
+
+  defp deps do
+    [
+      {:mime, "~> 1.0 or ~> 2.0"}
+    ]
+  end
+end
@@ -0,0 +1,9 @@
+defmodule Lib3.MixProject do
+  use Mix.Project
+
+  def project do
+    [app: :lib3, version: "1.0.0", deps: deps()]
+  end
+
+  defp deps, do: [{:ecto, "~> 3.10"}, {:ex_doc, ">= 0.0.0", only: :dev, runtime: false}]
+end
@@ -0,0 +1,90 @@
+package elixir
+
+import (
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/elixir"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/erlang"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_Elixir(t *testing.T) {
+
+	jason := tool.Dep("jason", "1.4.1")
+	plug := func() *model.DepGraph {
+		return tool.Dep("plug", "1.15.2",
+			tool.Dep("mime", "2.0.5"),
+		)
+	}
+	core := tool.Dep("core", "0.1.0", jason)
+
+	tool.RunTaskCase(t, elixir.Sca{})([]tool.TaskCase{
+
+		// mix.lock + mix.exs
+		{Path: "1", Result: tool.Dep("", "",
+			tool.Dep("demo", "0.3.0",
+				tool.DevDep("credo", "1.7.1",
+					tool.DevDep("bunt", "0.2.1"),
+				),
+				jason,
+				tool.Dep("local_lib", ""),
+				tool.Dep("mint", "1.0.0"),
+				tool.Dep("phoenix", "1.7.10", jason, plug()),
+				tool.Dep("plug_cowboy", "v2.6.1"),
+			),
+		)},
+
+		// umbrella项目
+		{Path: "2", Result: tool.Dep("", "", tool.Dep("", "",
+			core,
+			tool.Dep("web", "0.2.0", core, plug()),
+		))},
+
+		// 仅有mix.exs
+		{Path: "3", Result: tool.Dep("", "",
+			tool.Dep("lib3", "1.0.0",
+				tool.Dep("ecto", "~> 3.10"),
+				tool.DevDep("ex_doc", ">= 0.0.0"),
+			),
+		)},
+	})
+
+	// 本地组件及完整性校验值
+	tool.RunAttrCase(t, tool.Integrity, elixir.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{
+			"local_lib":   "local",
+			"mint":        "5b4a5b6f0c2d2f2c0b2b64b0bd7d8f8b",
+			"phoenix":     "cf784932e010fd736d656d7fead6a584a4498efefe5b8227e9f383bf15bb79d0",
+			"plug_cowboy": "",
+		}},
+	})
+
+	if purl := model.Purl("", "plug", "1.15.2", model.Lan_Elixir); purl != "pkg:hex/plug@1.15.2" {
+		t.Errorf("purl:%s", purl)
+	}
+}
+
+func Test_ElixirTerm(t *testing.T) {
+
+	term, err := erlang.ReadElixirTerm([]byte(`%{
+		# comment
+		"plug": {:hex, :plug, "1.15.2", [:mix], [{:mime, "~> 2.0", [hex: :mime, optional: false]},], "hexpm"},
+		"a.b": :"quoted atom",
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := term.(map[string]any)
+	plug := m["plug"].(erlang.Tuple)
+	if plug[0] != erlang.Atom("hex") || plug[2] != "1.15.2" {
+		t.Errorf("plug:%v", plug)
+	}
+	mime := plug[4].(erlang.List)[0].(erlang.Tuple)
+	if opt := mime[2].(erlang.List)[1].(erlang.Tuple); opt[0] != erlang.Atom("optional") || opt[1] != erlang.Atom("false") {
+		t.Errorf("keyword:%v", opt)
+	}
+	if m["a.b"] != erlang.Atom("quoted atom") {
+		t.Errorf("atom:%v", m["a.b"])
+	}
+}
@@ -0,0 +1,88 @@
+package erlang
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/erlang"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_Erlang(t *testing.T) {
+
+	tool.RunTaskCase(t, erlang.Sca{})([]tool.TaskCase{
+
+		// rebar.lock 1.2.0
+		{Path: "1", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("certifi", "2.9.0"),
+			tool.Dep("cowboy", "9e600f6c1df3c440bc196b66ebbc005d70107217"),
+			tool.Dep("cowlib", "2.12.1"),
+			tool.Dep("hackney", "1.18.1"),
+			tool.Dep("idna", "6.1.1"),
+			tool.Dep("jsx", "v3.1.0"),
+		))},
+
+		// 旧格式rebar.lock
+		{Path: "2", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("goldrush", "0.1.9"),
+			tool.Dep("lager", "3.9.2"),
+		))},
+	})
+
+	// 组件哈希 优先使用pkg_hash_ext
+	tool.RunAttrCase(t, tool.Integrity, erlang.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{
+			"certifi": "266DA46BAC2F7F65CB6D6E1FF7E44D5AF6B3AD5C4A0CC6D8A6B5A8DEB7C0E5F3",
+			"cowlib":  "A9FA9A625F1D2025FE6B462CB865881329B5CAFF8F1854D1CBC9F9533F00E1E1",
+			"idna":    "8A63070E9F7D0C62EB9D9FCB360A7DE382448200FBBD1B106CC96D3D8099DF8D",
+			"cowboy":  "",
+		}},
+	})
+
+	// 仅依赖层级为0的组件为直接依赖
+	tool.RunAttrCase(t, func(n *model.DepGraph) string { return strconv.FormatBool(n.Direct) }, erlang.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{
+			"certifi": "false",
+			"cowboy":  "true",
+			"cowlib":  "false",
+			"hackney": "true",
+			"idna":    "false",
+			"jsx":     "true",
+		}},
+	})
+}
+
+func Test_ErlangTerm(t *testing.T) {
+
+	terms, err := erlang.ReadTerms([]byte(`
+		%% comment
+		{deps, [cowboy, {jsx, "3.1.0"}, {'quoted atom', <<"bin"/utf8>>, <<104,105>>}]}.
+		#{key => [1, -2, 16#ff, 1.5e3]}.
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(terms) != 2 {
+		t.Fatalf("terms:%d", len(terms))
+	}
+	deps := terms[0].(erlang.Tuple)
+	if deps[0] != erlang.Atom("deps") {
+		t.Errorf("deps:%v", deps[0])
+	}
+	list := deps[1].(erlang.List)
+	if list[0] != erlang.Atom("cowboy") || list[1].(erlang.Tuple)[1] != "3.1.0" {
+		t.Errorf("list:%v", list)
+	}
+	if q := list[2].(erlang.Tuple); q[0] != erlang.Atom("quoted atom") || q[1] != "bin" || q[2] != "hi" {
+		t.Errorf("quoted:%v", q)
+	}
+	m := terms[1].(erlang.List)[0].(erlang.Tuple)
+	if nums := m[1].(erlang.List); nums[0] != 1 || nums[1] != -2 || nums[2] != 255 || nums[3] != 1500.0 {
+		t.Errorf("numbers:%v", nums)
+	}
+
+	if _, err := erlang.ReadTerms([]byte(`{a, b`)); err == nil {
+		t.Error("unterminated tuple should fail")
+	}
+}
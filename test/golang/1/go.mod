@@ -0,0 +1,13 @@
+module example.com/app
+
+go 1.20
+
+require (
+	example.com/dep v1.0.0
+	example.com/local v0.1.0
+	example.com/old v1.0.0
+)
+
+replace example.com/old => example.com/new v1.1.0
+
+replace example.com/local => ./local
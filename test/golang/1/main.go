@@ -0,0 +1,13 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/dep"
+	"example.com/local"
+	"example.com/old"
+)
+
+func main() {
+	fmt.Println(dep.Name(), local.Name(), old.Name())
+}
@@ -0,0 +1,18 @@
+module example.com/two
+
+go 1.21
+
+toolchain go1.21.5
+
+require (
+	example.com/dep v1.0.0
+	example.com/old v1.0.0 // indirect
+)
+
+require example.com/local v0.1.0
+
+replace (
+	// 替换为其他模块
+	example.com/old => example.com/new v1.1.0
+	example.com/local => ./local
+)
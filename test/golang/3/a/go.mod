@@ -0,0 +1,8 @@
+module example.com/a
+
+go 1.20
+
+require (
+	example.com/b v0.0.0
+	example.com/dep v1.0.0
+)
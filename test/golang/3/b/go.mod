@@ -0,0 +1,7 @@
+module example.com/b
+
+go 1.20
+
+require example.com/x v1.2.0
+
+replace example.com/x => ../x
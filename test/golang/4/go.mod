@@ -0,0 +1,10 @@
+module example.com/four
+
+go 1.20
+
+require (
+	example.com/dep v1.0.0
+	example.com/old v1.0.0
+)
+
+replace example.com/old => example.com/new v1.1.0
@@ -0,0 +1,20 @@
+module example.com/six
+
+go 1.20
+
+require (
+	example.com/lib v1.0.0
+	example.com/lib2 v1.0.0
+	example.com/local v0.1.0
+)
+
+require (
+	example.com/Upper v1.0.0 // indirect
+	example.com/dep v1.0.0 // indirect
+	example.com/old v1.0.0 // indirect
+	example.com/sub v0.3.0 // indirect
+)
+
+replace example.com/old => example.com/new v1.1.0
+
+replace example.com/local => ./local
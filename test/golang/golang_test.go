@@ -0,0 +1,325 @@
+package golang
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/common"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/filter"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/golang"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+// copyDir 复制目录
+func copyDir(t *testing.T, src, dst string) {
+	err := filepath.Walk(src, func(path string, info fs.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		os.MkdirAll(filepath.Join(dst, filepath.Dir(rel)), 0777)
+		return os.WriteFile(filepath.Join(dst, rel), data, 0666)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// goProxy 通过mod目录中的模块源码生成file://格式的GOPROXY
+// mod/<module>@<version>/ => proxy/<module>/@v/<version>.{info,mod,zip}
+// 模块路径中的大写字母转为!加小写字母
+func goProxy(t *testing.T, dir string) string {
+
+	proxy := filepath.Join(dir, "proxy")
+
+	mods, _ := filepath.Glob("mod/*/*@*")
+	for _, mod := range mods {
+
+		rel, _ := filepath.Rel("mod", mod)
+		path, version, _ := strings.Cut(filepath.ToSlash(rel), "@")
+		escaped := regexp.MustCompile(`[A-Z]`).ReplaceAllStringFunc(path, func(s string) string { return "!" + strings.ToLower(s) })
+		vdir := filepath.Join(proxy, filepath.FromSlash(escaped), "@v")
+		os.MkdirAll(vdir, 0777)
+
+		gomod, err := os.ReadFile(filepath.Join(mod, "go.mod"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(filepath.Join(vdir, version+".mod"), gomod, 0666)
+		os.WriteFile(filepath.Join(vdir, version+".info"), []byte(fmt.Sprintf(`{"Version":%q}`, version)), 0666)
+
+		f, err := os.Create(filepath.Join(vdir, version+".zip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := zip.NewWriter(f)
+		entries, _ := os.ReadDir(mod)
+		for _, e := range entries {
+			data, _ := os.ReadFile(filepath.Join(mod, e.Name()))
+			zw, _ := w.Create(fmt.Sprintf("%s@%s/%s", path, version, e.Name()))
+			zw.Write(data)
+		}
+		w.Close()
+		f.Close()
+	}
+
+	return "file://" + filepath.ToSlash(proxy)
+}
+
+// goBuild 离线构建go可执行文件 返回可执行文件所在目录
+func goBuild(t *testing.T, src string) string {
+
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+
+	dir := t.TempDir()
+	work := filepath.Join(dir, "src")
+	copyDir(t, src, work)
+
+	out := filepath.Join(dir, "bin")
+	cmd := exec.Command(gobin, "build", "-o", filepath.Join(out, "app"), ".")
+	cmd.Dir = work
+	cmd.Env = append(os.Environ(),
+		"GOPROXY="+goProxy(t, dir),
+		"GOSUMDB=off",
+		"GOFLAGS=-mod=mod -modcacherw -buildvcs=false",
+		"GOMODCACHE="+filepath.Join(dir, "modcache"),
+		"GOTOOLCHAIN=local",
+		"GOWORK=off",
+		"CGO_ENABLED=0",
+	)
+	if data, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %s\n%s", err, data)
+	}
+
+	return out
+}
+
+func Test_GoBinary(t *testing.T) {
+
+	bin := goBuild(t, "1")
+
+	goversion, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdlib := strings.Fields(strings.TrimPrefix(strings.TrimSpace(string(goversion)), "go"))[0]
+
+	tool.RunTaskCase(t, golang.Sca{})([]tool.TaskCase{
+		{Path: bin, Result: tool.Dep("", "",
+			tool.Dep("example.com/app", "",
+				tool.Dep("example.com/dep", "v1.0.0"),
+				tool.Dep("example.com/local", "v0.1.0"),
+				tool.Dep("example.com/new", "v1.1.0"),
+				tool.Dep("go", stdlib),
+			),
+		)},
+	})
+
+	root := golang.ParseGoBinary(model.NewFile(filepath.Join(bin, "app"), "app"))
+	if root == nil {
+		t.Fatal("parse go binary fail")
+	}
+	for _, dep := range root.Children {
+		switch dep.Name {
+		case "example.com/dep":
+			if !strings.HasPrefix(dep.Integrity, "h1:") {
+				t.Errorf("%s integrity:%s", dep.Index(), dep.Integrity)
+			}
+		case "example.com/new":
+			if dep.Override != "example.com/old v1.0.0 => example.com/new v1.1.0" || !strings.HasPrefix(dep.Integrity, "h1:") {
+				t.Errorf("%s override:%s integrity:%s", dep.Index(), dep.Override, dep.Integrity)
+			}
+		case "example.com/local":
+			if !dep.Local || dep.Override != "example.com/local v0.1.0 => ./local" {
+				t.Errorf("%s local:%v override:%s", dep.Index(), dep.Local, dep.Override)
+			}
+		}
+	}
+
+	// 非go可执行文件
+	if golang.ParseGoBinary(model.NewFile(filepath.Join("1", "go.mod"), "go.mod")) != nil {
+		t.Error("go.mod is not a go binary")
+	}
+
+	// 可执行文件需要通过文件名及文件头确认
+	for name, want := range map[string]bool{"app": true, "app.exe": true, "LICENSE": false, "Makefile": false, "go.mod": false} {
+		if filter.Executable(name) != want {
+			t.Errorf("%s executable:%v", name, !want)
+		}
+	}
+	for path, want := range map[string]bool{filepath.Join(bin, "app"): true, filepath.Join("1", "go.mod"): false} {
+		model.NewFile(path, path).OpenReader(func(reader io.Reader) {
+			if filter.ExecutableMagic(reader) != want {
+				t.Errorf("%s executable magic:%v", path, !want)
+			}
+		})
+	}
+}
+
+func Test_GoMod(t *testing.T) {
+
+	// 禁止下载模块 使用静态解析
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOWORK", "off")
+	// goproxy中没有相关模块
+	golang.RegisterGoproxyRepo(common.RepoConfig{Url: "file://" + filepath.ToSlash(t.TempDir())})
+
+	// workspace中的模块
+	b := tool.Dep("example.com/b", "",
+		tool.Dep("example.com/x", "v1.2.0"),
+	)
+
+	tool.RunTaskCase(t, golang.Sca{})([]tool.TaskCase{
+		// replace
+		{Path: "2", Result: tool.Dep("", "",
+			tool.Dep("example.com/two", "",
+				tool.Dep("example.com/dep", "v1.0.0"),
+				tool.Dep("example.com/new", "v1.1.0"),
+				tool.Dep("example.com/local", "v0.1.0"),
+				tool.Dep("go", "1.21.5"),
+			),
+		)},
+		// go.work
+		{Path: "3", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("example.com/a", "",
+					b,
+					tool.Dep("example.com/dep", "v1.0.1"),
+				),
+				b,
+				tool.Dep("go", "1.20"),
+			),
+		)},
+		// vendor/modules.txt
+		{Path: "4", Result: tool.Dep("", "",
+			tool.Dep("example.com/four", "",
+				tool.Dep("example.com/dep", "v1.0.0"),
+				tool.Dep("example.com/new", "v1.1.0"),
+				tool.Dep("go", "1.20"),
+			),
+		)},
+		// go1.17之前的go.mod 间接依赖从go.sum中获取
+		{Path: "5", Result: tool.Dep("", "",
+			tool.Dep("example.com/five", "",
+				tool.Dep("example.com/dep", "v1.0.0"),
+				tool.Dep("example.com/sub", "v0.3.0"),
+				tool.Dep("go", "1.16"),
+			),
+		)},
+	})
+
+	mod := golang.ReadGoMod(model.NewFile(filepath.Join("2", "go.mod"), "go.mod"))
+	sum := golang.ReadGoSum(model.NewFile(filepath.Join("2", "go.sum"), "go.sum"))
+	for _, dep := range golang.ParseGomod(mod, sum).Children {
+		switch dep.Name {
+		case "example.com/dep":
+			if dep.Integrity != "h1:dep0000000000000000000000000000000000000000=" {
+				t.Errorf("%s integrity:%s", dep.Index(), dep.Integrity)
+			}
+		case "example.com/new":
+			if dep.Override != "example.com/old => example.com/new v1.1.0" || dep.Integrity != "h1:new0000000000000000000000000000000000000000=" {
+				t.Errorf("%s override:%s integrity:%s", dep.Index(), dep.Override, dep.Integrity)
+			}
+		case "example.com/local":
+			if !dep.Local || dep.Override != "example.com/local => ./local" || dep.Integrity != "" {
+				t.Errorf("%s local:%v override:%s", dep.Index(), dep.Local, dep.Override)
+			}
+		case "go":
+			if dep.Language != model.Lan_Runtime || dep.Version != "1.21.5" {
+				t.Errorf("%s language:%s", dep.Index(), dep.Language)
+			}
+			// go标准库
+			purl := model.Purl(dep.Vendor, dep.Name, dep.Version, dep.Language)
+			if purl != "pkg:golang/stdlib@1.21.5" {
+				t.Errorf("purl:%s", purl)
+			}
+			if _, name, version, language := model.ParsePurl(purl); name != "go" || version != "1.21.5" || language != model.Lan_Runtime {
+				t.Errorf("parse purl name:%s version:%s language:%s", name, version, language)
+			}
+		default:
+			t.Errorf("unexpected dep %s", dep.Index())
+		}
+	}
+}
+
+func Test_GoModGraph(t *testing.T) {
+
+	// go1.21及以上 go mod graph 输出go版本及toolchain
+	output := []byte(`example.com/app example.com/dep@v1.0.0
+example.com/app go@1.22
+example.com/app toolchain@go1.22.3
+example.com/dep@v1.0.0 example.com/x@v1.2.0
+example.com/dep@v1.0.0 go@1.21
+go@1.22 toolchain@go1.22
+toolchain@go1.22.3 go@1.22.3
+`)
+	res := golang.ParseGoModGraph(model.NewFile("go.mod", "go.mod"), output)
+	std := tool.Dep("", "",
+		tool.Dep("example.com/app", "",
+			tool.Dep("example.com/dep", "v1.0.0",
+				tool.Dep("example.com/x", "v1.2.0"),
+			),
+			tool.Dep("go", "1.22.3"),
+		),
+	)
+	if tool.Diff(res, std) {
+		t.Errorf("res:\n%sstd:\n%s", res.Tree(false, true), std.Tree(false, true))
+	}
+}
+
+func Test_GoModMVS(t *testing.T) {
+
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOWORK", "off")
+	golang.RegisterGoproxyRepo(common.RepoConfig{Url: goProxy(t, t.TempDir())})
+
+	dep := tool.Dep("example.com/dep", "v1.0.0")
+	sub := tool.Dep("example.com/sub", "v0.3.0")
+
+	tool.RunTaskCase(t, golang.Sca{})([]tool.TaskCase{
+		{Path: "6", Result: tool.Dep("", "",
+			tool.Dep("example.com/local", "",
+				tool.Dep("example.com/dep", "v1.0.0"),
+				tool.Dep("go", "1.20"),
+			),
+			tool.Dep("example.com/six", "",
+				tool.Dep("example.com/lib", "v1.0.0", sub),
+				tool.Dep("example.com/lib2", "v1.0.0",
+					tool.Dep("example.com/Upper", "v1.0.0"),
+					sub,
+				),
+				tool.Dep("example.com/local", "v0.1.0", dep),
+				// 仅被未选择的example.com/sub@v0.2.0依赖
+				tool.Dep("example.com/new", "v1.1.0"),
+				tool.Dep("go", "1.20"),
+			),
+		)},
+	})
+
+	// 任务取消时不再请求goproxy
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mod := golang.ReadGoMod(model.NewFile(filepath.Join("6", "go.mod"), "go.mod"))
+	if graph := golang.ResolveGomod(ctx, mod, nil); graph != nil {
+		t.Errorf("resolve with canceled context:\n%s", graph.Tree(false, true))
+	}
+}
@@ -0,0 +1,8 @@
+module example.com/lib2
+
+go 1.20
+
+require (
+	example.com/Upper v1.0.0
+	example.com/sub v0.3.0
+)
@@ -0,0 +1,5 @@
+module example.com/lib
+
+go 1.20
+
+require example.com/sub v0.2.0
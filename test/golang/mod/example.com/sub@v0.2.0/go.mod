@@ -0,0 +1,5 @@
+module example.com/sub
+
+go 1.20
+
+require example.com/old v1.0.0
@@ -0,0 +1,12 @@
+plugins {
+    application
+}
+
+dependencies {
+    implementation(platform(libs.jackson.bom))
+    implementation("com.fasterxml.jackson.core:jackson-databind")
+    implementation(libs.commons.lang3)
+    implementation(project(":lib"))
+    testImplementation(libs.junit)
+    annotationProcessor("org.projectlombok:lombok:1.18.30")
+}
@@ -0,0 +1,7 @@
+plugins {
+    `java-library`
+}
+
+dependencies {
+    api("com.google.guava:guava:32.1.2-jre")
+}
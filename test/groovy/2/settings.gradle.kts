@@ -0,0 +1,3 @@
+rootProject.name = "demo"
+
+include(":app", ":lib")
@@ -0,0 +1,67 @@
+package groovy
+
+import (
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/groovy"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/java"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_Gradle(t *testing.T) {
+
+	// 使用本地数据源 避免访问maven仓库
+	java.RegisterMavenOrigin(func(groupId, artifactId, version string) *java.Pom {
+		if groupId == "com.fasterxml.jackson" && artifactId == "jackson-bom" {
+			return &java.Pom{DependencyManagement: []*java.PomDependency{
+				{GroupId: "com.fasterxml.jackson.core", ArtifactId: "jackson-databind", Version: version},
+			}}
+		}
+		return nil
+	})
+
+	lib := tool.Dep("lib", "",
+		tool.Dep3("com.google.guava", "guava", "32.1.2-jre"),
+	)
+
+	tool.RunTaskCase(t, groovy.Sca{})([]tool.TaskCase{
+
+		// gradle.lockfile
+		{Path: "1", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep3("com.google.guava", "failureaccess", "1.0.1"),
+				tool.Dep3("com.google.guava", "guava", "31.1-jre"),
+				tool.DevDep3("junit", "junit", "4.13.2"),
+				tool.DevDep3("org.hamcrest", "hamcrest-core", "1.3"),
+				tool.DevDep3("org.projectlombok", "lombok", "1.18.30"),
+				tool.Dep3("org.springframework.boot", "spring-boot-gradle-plugin", "3.1.0"),
+			),
+		)},
+
+		// settings.gradle.kts & libs.versions.toml & platform
+		{Path: "2", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("app", "",
+					tool.Dep3("com.fasterxml.jackson.core", "jackson-databind", "2.15.2"),
+					tool.Dep3("org.apache.commons", "commons-lang3", "3.12.0"),
+					tool.DevDep3("junit", "junit", "4.13.2"),
+					tool.DevDep3("org.projectlombok", "lombok", "1.18.30"),
+					lib,
+				),
+				lib,
+			),
+		)},
+
+		// plugins & pluginManagement
+		{Path: "3", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("demo", "",
+					tool.Dep3("com.diffplug.spotless", "com.diffplug.spotless.gradle.plugin", "6.20.0"),
+					tool.Dep3("com.google.guava", "guava", "32.1.2-jre"),
+					tool.Dep3("io.spring.dependency-management", "io.spring.dependency-management.gradle.plugin", "1.1.0"),
+					tool.Dep3("org.springframework.boot", "org.springframework.boot.gradle.plugin", "3.1.0"),
+				),
+			),
+		)},
+	})
+}
@@ -0,0 +1,35 @@
+package ivy
+
+import (
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/ivy"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/java"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_Ivy(t *testing.T) {
+
+	// 使用本地数据源 避免访问maven仓库
+	java.RegisterMavenOrigin(func(groupId, artifactId, version string) *java.Pom {
+		deps := map[string][]*java.PomDependency{
+			"commons-text": {{GroupId: "org.apache.commons", ArtifactId: "commons-lang3", Version: "3.12.0"}},
+			"guava":        {{GroupId: "com.google.guava", ArtifactId: "failureaccess", Version: "1.0.1"}},
+			"junit":        {{GroupId: "org.hamcrest", ArtifactId: "hamcrest-core", Version: "1.3"}},
+		}
+		return &java.Pom{Dependencies: deps[artifactId]}
+	})
+
+	tool.RunTaskCase(t, ivy.Sca{})([]tool.TaskCase{
+		{Path: "1", Result: tool.Dep("", "",
+			tool.Dep3("com.example", "app", "1.0",
+				tool.Dep3("org.apache.commons", "commons-text", "1.10.0",
+					tool.Dep3("org.apache.commons", "commons-lang3", "3.12.0"),
+				),
+				tool.Dep3("com.google.guava", "guava", "32.1.2-jre"),
+				tool.Dep3("org.postgresql", "postgresql", "42.6.0"),
+				tool.DevDep3("junit", "junit", "4.13.2"),
+			),
+		)},
+	})
+}
@@ -1,9 +1,12 @@
 package java
 
 import (
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/common"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/java"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
 )
@@ -204,3 +207,90 @@ func Test_JavaWithStatic(t *testing.T) {
 func Test_JavaWithMvn(t *testing.T) {
 	tool.RunTaskCase(t, java.Sca{NotUseStatic: true})(cases)
 }
+
+func Test_JavaJdk(t *testing.T) {
+	tool.RunTaskCase(t, java.Sca{NotUseMvn: true})([]tool.TaskCase{
+		// 使用maven.compiler属性记录jdk版本 release优先
+		{Path: "18", Result: tool.Dep("", "",
+			tool.Dep3("com.foo", "demo", "1.0",
+				tool.Dep("jdk", "8"),
+			),
+			tool.Dep3("com.foo", "mod", "1.0",
+				tool.Dep("jdk", "17"),
+			),
+		)},
+	})
+}
+
+func Test_JavaPlugin(t *testing.T) {
+	// 构建插件及扩展 mvn dependency:tree的结果同样需要补充
+	for _, s := range []java.Sca{{NotUseMvn: true}, {NotUseStatic: true}} {
+		tool.RunAttrCase(t, func(n *model.DepGraph) string {
+			return string(n.Scope) + ":" + n.Version
+		}, s)([]tool.AttrCase{
+			// <build><plugins> pluginManagement补全版本 继承parent中的插件 .mvn/extensions.xml
+			{Path: "19", Want: map[string]string{
+				"maven-compiler-plugin": "plugin:3.11.0",
+				"maven-surefire-plugin": "plugin:3.1.2",
+				"maven-enforcer-plugin": "plugin:3.4.1",
+				"wagon-ssh":             "plugin:3.5.3",
+				"os-maven-plugin":       "plugin:1.7.1",
+			}},
+		})
+	}
+}
+
+func Test_JavaPluginInherit(t *testing.T) {
+
+	// 数据源返回同一个parent 插件版本不能在模块间相互影响
+	parent := java.ReadPom(strings.NewReader(`<project>
+	<groupId>com.corp</groupId>
+	<artifactId>corp-parent</artifactId>
+	<version>1.0</version>
+	<properties>
+		<enforcer.version>3.4.1</enforcer.version>
+		<surefire.version>3.1.2</surefire.version>
+	</properties>
+	<build>
+		<pluginManagement>
+			<plugins>
+				<plugin>
+					<groupId>org.apache.maven.plugins</groupId>
+					<artifactId>maven-surefire-plugin</artifactId>
+					<version>${surefire.version}</version>
+				</plugin>
+			</plugins>
+		</pluginManagement>
+		<plugins>
+			<plugin>
+				<groupId>org.apache.maven.plugins</groupId>
+				<artifactId>maven-enforcer-plugin</artifactId>
+				<version>${enforcer.version}</version>
+			</plugin>
+		</plugins>
+	</build>
+</project>`))
+	java.RegisterMavenOrigin(func(groupId, artifactId, version string) *java.Pom {
+		if artifactId == parent.ArtifactId {
+			return parent
+		}
+		var p *java.Pom
+		java.DownloadPomFromRepo(java.PomDependency{GroupId: groupId, ArtifactId: artifactId, Version: version}, func(r io.Reader) {
+			p = java.ReadPom(r)
+		})
+		return p
+	})
+
+	tool.RunTaskCase(t, java.Sca{NotUseMvn: true})([]tool.TaskCase{
+		{Path: "20", Result: tool.Dep("", "",
+			tool.Dep3("com.foo", "a", "1.0",
+				tool.Dep3("org.apache.maven.plugins", "maven-surefire-plugin", "3.0.0"),
+				tool.Dep3("org.apache.maven.plugins", "maven-enforcer-plugin", "3.3.0"),
+			),
+			tool.Dep3("com.foo", "b", "1.0",
+				tool.Dep3("org.apache.maven.plugins", "maven-surefire-plugin", "3.1.2"),
+				tool.Dep3("org.apache.maven.plugins", "maven-enforcer-plugin", "3.4.1"),
+			),
+		)},
+	})
+}
@@ -1,8 +1,14 @@
 package javascript
 
 import (
+	"context"
+	"strconv"
 	"testing"
 
+	"github.com/Masterminds/semver/v3"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/javascript"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
 )
@@ -33,6 +39,24 @@ func Test_JavaScript(t *testing.T) {
 		),
 	)
 
+	tokens := tool.Dep("js-tokens", "4.0.0")
+	loose := tool.Dep("loose-envify", "1.4.0", tokens)
+	react := tool.Dep("react", "18.2.0", loose)
+	reactDom := tool.Dep("react-dom", "18.2.0",
+		loose,
+		react,
+		tool.Dep("scheduler", "0.23.0", loose),
+	)
+
+	pnpm := tool.Dep("", "",
+		tool.Dep("pnpm-test", "1.0.0",
+			react,
+			reactDom,
+			tool.Dep("fsevents", "2.3.3"),
+			tool.DevDep("typescript", "5.2.2"),
+		),
+	)
+
 	tool.RunTaskCase(t, javascript.Sca{})([]tool.TaskCase{
 		// package.lock
 		{Path: "1", Result: std},
@@ -44,5 +68,152 @@ func Test_JavaScript(t *testing.T) {
 		{Path: "4", Result: std},
 		// simple
 		{Path: "5", Result: std},
+		// pnpm-lock.yaml v6
+		{Path: "6", Result: pnpm},
+		// pnpm-lock.yaml v5
+		{Path: "7", Result: pnpm},
+		// pnpm-lock.yaml v9 workspace
+		{Path: "8", Result: tool.Dep("", "",
+			tool.Dep("pnpm-workspace", "1.0.0",
+				tool.DevDep("typescript", "5.2.2"),
+			),
+			tool.Dep("app", "0.1.0",
+				tool.Dep("lib", "0.2.0", react),
+				reactDom,
+			),
+			tool.Dep("lib", "0.2.0",
+				tool.Dep("react", "18.2.0",
+					tool.Dep("loose-envify", "1.4.0",
+						tool.Dep("js-tokens", "4.0.0"),
+					),
+				),
+			),
+		)},
+		// yarn berry workspace
+		{Path: "9", Result: tool.Dep("", "",
+			tool.Dep("app", "0.1.0",
+				tool.Dep("lib", "0.2.0", react),
+				reactDom,
+				tokens,
+			),
+			tool.Dep("berry-workspace", "1.0.0",
+				tool.DevDep("typescript", "5.2.2"),
+			),
+			tool.Dep("lib", "0.2.0",
+				tool.Dep("react", "18.2.0",
+					tool.Dep("loose-envify", "1.4.0",
+						tool.Dep("js-tokens", "4.0.0"),
+					),
+				),
+				tool.DevDep("typescript", "5.2.2"),
+			),
+		)},
+		// npm workspace & npm-shrinkwrap.json
+		{Path: "10", Result: tool.Dep("", "",
+			tool.Dep("npm-workspace", "1.0.0",
+				tool.DevDep("typescript", "5.2.2"),
+			),
+			tool.Dep("app", "0.1.0",
+				tool.Dep("lib", "0.2.0", react),
+				reactDom,
+			),
+			tool.Dep("lib", "0.2.0",
+				tool.Dep("react", "18.2.0",
+					tool.Dep("loose-envify", "1.4.0",
+						tool.Dep("js-tokens", "4.0.0"),
+					),
+				),
+				tool.DevDep("typescript", "5.2.2"),
+			),
+		)},
 	})
 }
+
+func Test_JavaScriptEngines(t *testing.T) {
+	tool.RunTaskCase(t, javascript.Sca{})([]tool.TaskCase{
+		// engines.node记录为运行时组件 数组格式的engines忽略
+		{Path: "13", Result: tool.Dep("", "",
+			tool.Dep("engines-test", "1.0.0",
+				tool.Dep("a", "1.0.0"),
+				tool.Dep("node", ">=18.0.0"),
+			),
+			tool.Dep("legacy-engines", "0.1.0"),
+		)},
+	})
+}
+
+func Test_JavaScriptLocal(t *testing.T) {
+	// yarn berry中通过本地路径引用的组件
+	tool.RunAttrCase(t, func(n *model.DepGraph) string { return strconv.FormatBool(n.Local) }, javascript.Sca{})([]tool.AttrCase{
+		{Path: "14", Want: map[string]string{
+			"local-portal": "true",
+			"local-link":   "true",
+			"local-file":   "true",
+			"js-tokens":    "false",
+		}},
+	})
+}
+
+func Test_JavaScriptOverride(t *testing.T) {
+
+	registry := map[string][]*javascript.PackageJson{
+		"a": {{Name: "a", Version: "1.0.0", Dependencies: map[string]string{"b": "^1.0.0", "d": "^2.0.0"}}},
+		"b": {{Name: "b", Version: "1.0.0"}, {Name: "b", Version: "1.0.1"}, {Name: "b", Version: "1.1.0"}},
+		"c": {{Name: "c", Version: "1.0.0", Dependencies: map[string]string{"d": "^2.0.0"}}},
+		"d": {{Name: "d", Version: "1.0.0"}, {Name: "d", Version: "2.0.0"}},
+	}
+	javascript.RegisterNpmOrigin(func(name, version string) *javascript.PackageJson {
+		c, err := semver.NewConstraint(version)
+		if err != nil {
+			return nil
+		}
+		var latest *javascript.PackageJson
+		for _, pkg := range registry[name] {
+			if v, err := semver.NewVersion(pkg.Version); err == nil && c.Check(v) {
+				latest = pkg
+			}
+		}
+		return latest
+	})
+
+	d := tool.Dep("d", "1.0.0")
+	b := tool.Dep("b", "1.0.1")
+	d2 := tool.Dep("d", "2.0.0")
+
+	tool.RunTaskCase(t, javascript.Sca{})([]tool.TaskCase{
+		// npm overrides 包括版本选择器、嵌套规则及$引用
+		{Path: "11", Result: tool.Dep("", "",
+			tool.Dep("override-test", "1.0.0",
+				tool.Dep("a", "1.0.0", b, d),
+				tool.Dep("c", "1.0.0", tool.Dep("d", "2.0.0")),
+				d,
+			),
+		)},
+		// yarn resolutions
+		{Path: "12", Result: tool.Dep("", "",
+			tool.Dep("resolutions-test", "1.0.0",
+				tool.Dep("a", "1.0.0", b, d2),
+				tool.Dep("c", "1.0.0", d2),
+			),
+		)},
+	})
+
+	// 覆盖规则需要记录在受影响的组件上
+	for path, want := range map[string]map[string]string{
+		"11": {"b:1.0.1": "1.0.1", "d:1.0.0": "1.0.0", "d:2.0.0": ""},
+		"12": {"b:1.0.1": "1.0.1", "d:2.0.0": ""},
+	} {
+		r := opensca.RunTask(context.Background(), &opensca.TaskArg{
+			DataOrigin: path,
+			Sca:        []sca.Sca{javascript.Sca{}},
+		})
+		for _, dep := range r.Deps {
+			dep.ForEachNode(func(p, n *model.DepGraph) bool {
+				if override, ok := want[n.Name+":"+n.Version]; ok && n.Override != override {
+					t.Errorf("%s %s override:%s want:%s", path, n.Index(), n.Override, override)
+				}
+				return true
+			})
+		}
+	}
+}
@@ -0,0 +1,10 @@
+/*!
+  * Bootstrap v4.3.1 (https://getbootstrap.com/)
+  * Copyright 2011-2019 The Bootstrap Authors (https://github.com/twbs/bootstrap/graphs/contributors)
+  * Licensed under MIT (https://github.com/twbs/bootstrap/blob/master/LICENSE)
+  */
+!function(t,e){"object"==typeof exports&&"undefined"!=typeof module?e(exports,require("jquery")):e((t=t||self).bootstrap={},t.jQuery)}(this,function(t,p){"use strict";
+/**!
+ * @popperjs/core v2.11.8 - MIT License
+ */
+var n="4.3.1"});
@@ -0,0 +1 @@
+window.Internal={version:"x"};
@@ -0,0 +1,51 @@
+package jslib
+
+import (
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/jslib"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_JsLib(t *testing.T) {
+
+	// 自定义特征库
+	err := jslib.RegisterSignatures([]byte(`{
+		"internal-widget": {
+			"extractors": {
+				"hashes": {"b4560caa6f44d29ccce059afd2b9f67ec12ab23e": "1.2.0"}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tool.RunTaskCase(t, jslib.Sca{})([]tool.TaskCase{
+		// license banner 忽略node_modules
+		{Path: "1", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("jquery", "3.4.1"),
+			),
+		)},
+		// bundle中包含多个组件
+		{Path: "2", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("@popperjs/core", "2.11.8"),
+				tool.Dep("bootstrap", "4.3.1"),
+			),
+		)},
+		// 文件名
+		{Path: "3", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("lodash", "4.17.15"),
+			),
+		)},
+		// 文件hash
+		{Path: "4", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("internal-widget", "1.2.0"),
+			),
+		)},
+	})
+}
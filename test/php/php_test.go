@@ -3,31 +3,88 @@ package php
 import (
 	"testing"
 
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/php"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
 )
 
 func Test_Php(t *testing.T) {
 
-	std := tool.Dep("", "",
-		tool.Dep("opensca/test", "",
-			tool.Dep("http-interop/http-factory-guzzle", "1.2.0",
-				tool.Dep("guzzlehttp/psr7", "2.6.1",
-					tool.Dep("ralouphie/getallheaders", "3.0.3"),
+	message := tool.Dep("psr/http-message", "2.0")
+	factory := tool.Dep("psr/http-factory", "1.0.2", message)
+	std := func(runtime ...*model.DepGraph) *model.DepGraph {
+		return tool.Dep("", "",
+			tool.Dep("opensca/test", "", append([]*model.DepGraph{
+				tool.Dep("http-interop/http-factory-guzzle", "1.2.0",
+					tool.Dep("guzzlehttp/psr7", "2.6.1",
+						factory,
+						message,
+						tool.Dep("ralouphie/getallheaders", "3.0.3"),
+					),
+					factory,
 				),
-				tool.Dep("psr/http-factory", "1.0.2"),
-			),
-			tool.Dep("psr/http-message", "2.0"),
-		),
-	)
+				message,
+			}, runtime...)...),
+		)
+	}
 
 	tool.RunTaskCase(t, php.Sca{})([]tool.TaskCase{
 
-		// composer.lock
-		{Path: "1", Result: std},
+		// composer.lock platform-overrides中的php版本
+		{Path: "1", Result: std(tool.Dep("php", "7.4.33"))},
 
-		// composer.json
-		{Path: "2", Result: std},
+		// composer.json 版本约束不作为运行时版本
+		{Path: "2", Result: std()},
 	})
 
+	// 运行时组件
+	if purl := model.Purl("", "php", "7.4.33", model.Lan_Runtime); purl != "pkg:generic/php@7.4.33?ecosystem=composer" {
+		t.Errorf("purl:%s", purl)
+	}
+	if purl := model.Purl("", "ext-json", "8.1.2", model.Lan_Runtime); purl != "pkg:generic/ext-json@8.1.2?ecosystem=composer" {
+		t.Errorf("purl:%s", purl)
+	}
+
+}
+
+func Test_PhpInstalled(t *testing.T) {
+
+	laravel := tool.Dep("laravel/framework", "v10.0.0",
+		tool.Dep("guzzlehttp/guzzle", "7.5.0"),
+		tool.Dep("psr/log", "3.0.0"),
+	)
+
+	tool.RunTaskCase(t, php.Sca{})([]tool.TaskCase{
+
+		// 平台组件及replace/provide
+		{Path: "3", Result: tool.Dep("", "",
+			tool.Dep("opensca/app", "",
+				laravel,
+				tool.Dep("php", "8.1.2"),
+				tool.DevDep("phpunit/phpunit", "10.0.0"),
+				tool.Dep("spatie/laravel-package-tools", "1.14.0", laravel),
+			),
+		)},
+
+		// installed.json composer 2.x
+		{Path: "4", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("app/logger-user", "1.0.0",
+				tool.Dep("monolog/monolog", "3.4.0",
+					tool.Dep("psr/log", "3.0.0"),
+				),
+			),
+			tool.DevDep("phpunit/phpunit", "10.3.2",
+				tool.DevDep("sebastian/diff", "5.0.3"),
+			),
+		))},
+
+		// composer.json + installed.json composer 1.x
+		{Path: "5", Result: tool.Dep("", "",
+			tool.Dep("opensca/legacy", "",
+				tool.Dep("monolog/monolog", "2.9.1",
+					tool.Dep("psr/log", "1.1.4"),
+				),
+			),
+		)},
+	})
 }
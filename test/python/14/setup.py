@@ -0,0 +1,15 @@
+import os
+from setuptools import setup
+
+# 执行时会在当前目录创建文件 静态解析不应执行
+open(os.path.join(os.path.dirname(__file__), "executed"), "w").close()
+
+setup(
+    name="setup-demo",
+    version="0.1.0",
+    python_requires=">=3.8",
+    install_requires=[
+        "elasticsearch>=8,<8.10",
+        'pywin32>=300; sys_platform == "win32"',
+    ],
+)
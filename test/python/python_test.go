@@ -1,14 +1,96 @@
 package python
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/common"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/python"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
 )
 
+// pypiRepo 模拟pypi仓库 elasticsearch等组件使用json api idna使用simple api
+func pypiRepo(t *testing.T) {
+
+	type release struct {
+		requires []string
+		yanked   bool
+	}
+	projects := map[string]map[string]release{
+		"elasticsearch": {
+			"7.17.9":    {},
+			"8.9.0":     {requires: []string{"elastic-transport<9,>=8", `aiohttp<4,>=3; extra == "async"`}},
+			"8.10.0rc1": {},
+		},
+		"elastic-transport": {
+			"8.4.0": {requires: []string{"urllib3<2,>=1.26.2", "certifi"}},
+			"8.4.1": {yanked: true},
+			"9.0.0": {},
+		},
+		"certifi": {"2023.7.22": {}},
+		"urllib3": {"1.26.16": {}, "2.0.4": {}},
+		"requests": {
+			"2.31.0": {requires: []string{"idna<4,>=2.5", `PySocks!=1.5.7,>=1.5.6; extra == "socks"`, `chardet<6,>=3.0.2; extra == "use-chardet-on-py3"`}},
+		},
+		"pysocks":  {"1.7.1": {}},
+		"pywin32":  {"306": {}},
+		"tomli":    {"2.0.1": {}},
+		"colorama": {"0.4.6": {}},
+	}
+
+	jsonApi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		project, ok := projects[s[0]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		data := map[string]any{}
+		if len(s) == 2 {
+			releases := map[string]any{}
+			for v, rel := range project {
+				releases[v] = []map[string]any{{"yanked": rel.yanked}}
+			}
+			data["info"] = map[string]any{"name": s[0]}
+			data["releases"] = releases
+		} else {
+			data["info"] = map[string]any{"name": s[0], "version": s[1], "requires_dist": project[s[1]].requires}
+		}
+		json.NewEncoder(w).Encode(data)
+	}))
+	t.Cleanup(jsonApi.Close)
+
+	simpleApi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/simple/idna/":
+			for _, v := range []string{"3.4", "3.5", "3.6", "3.7.dev0"} {
+				fmt.Fprintf(w, `<a href="../../files/idna-%s-py3-none-any.whl#sha256=00" data-core-metadata="true">idna-%s-py3-none-any.whl</a>`, v, v)
+				fmt.Fprintf(w, `<a href="../../files/idna-%s.tar.gz#sha256=00">idna-%s.tar.gz</a>`, v, v)
+			}
+		case "/files/idna-3.6-py3-none-any.whl.metadata":
+			fmt.Fprint(w, "Metadata-Version: 2.1\nName: idna\nVersion: 3.6\nLicense-Expression: BSD-3-Clause\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(simpleApi.Close)
+
+	python.RegisterPypiRepo(
+		common.RepoConfig{Url: jsonApi.URL},
+		common.RepoConfig{Url: simpleApi.URL + "/simple"},
+	)
+}
+
 func Test_Python(t *testing.T) {
 
+	pypiRepo(t)
+
 	tool.RunTaskCase(t, python.Sca{})([]tool.TaskCase{
 
 		// rquirements.txt
@@ -38,6 +120,197 @@ func Test_Python(t *testing.T) {
 			tool.Dep("certifi", "2023.7.22"),
 			tool.Dep("urllib3", "1.26.16"),
 		))},
+
+		// requirements.in 版本约束
+		{Path: "11", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("elasticsearch", "8.9.0",
+				tool.Dep("elastic-transport", "8.4.0",
+					tool.Dep("certifi", "2023.7.22"),
+					tool.Dep("urllib3", "1.26.16"),
+				),
+			),
+			tool.Dep("idna", "3.6"),
+		))},
+
+		// Pipfile 开发依赖的子依赖
+		{Path: "15", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("certifi", "2023.7.22"),
+			tool.DevDep("elasticsearch", "8.9.0",
+				tool.DevDep("elastic-transport", "8.4.0",
+					tool.DevDep("certifi", "2023.7.22"),
+					tool.DevDep("urllib3", "1.26.16"),
+				),
+			),
+		))},
+
+		// setup.py 静态解析
+		{Path: "14", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("python", ">=3.8"),
+			tool.Dep("elasticsearch", "8.9.0",
+				tool.Dep("elastic-transport", "8.4.0",
+					tool.Dep("certifi", "2023.7.22"),
+					tool.Dep("urllib3", "1.26.16"),
+				),
+			),
+		))},
 	})
 
+	if _, err := os.Stat("14/executed"); err == nil {
+		os.Remove("14/executed")
+		t.Error("setup.py executed")
+	}
+}
+
+func Test_PythonStatic(t *testing.T) {
+
+	requests := func(idna *model.DepGraph) *model.DepGraph {
+		return tool.Dep("requests", "2.31.0",
+			tool.Dep("certifi", "2023.11.17"),
+			idna,
+			tool.Dep("urllib3", "2.1.0"),
+		)
+	}
+	pytest := func() *model.DepGraph {
+		return tool.DevDep("pytest", "7.4.3", tool.DevDep("iniconfig", "2.0.0"))
+	}
+
+	idna := tool.Dep("idna", "3.6")
+	lib := tool.Dep("lib", "0.2.0", idna)
+	lib.Local = true
+
+	tool.RunTaskCase(t, python.Sca{})([]tool.TaskCase{
+
+		// poetry.lock
+		{Path: "4", Result: tool.Dep("", "",
+			tool.Dep("poetry-demo", "0.1.0",
+				tool.Dep("python", "^3.9"),
+				requests(tool.Dep("idna", "3.6")),
+				tool.Dep("pysocks", "1.7.1"),
+				pytest(),
+			),
+		)},
+
+		// pdm.lock
+		{Path: "5", Result: tool.Dep("", "",
+			tool.Dep("pdm-demo", "0.1.0",
+				tool.Dep("python", ">=3.9"),
+				requests(tool.Dep("idna", "3.6")),
+				pytest(),
+			),
+		)},
+
+		// uv.lock workspace
+		{Path: "6", Result: tool.Dep("", "",
+			tool.Dep("uv-demo", "0.1.0",
+				tool.Dep("python", ">=3.12"),
+				lib,
+				requests(idna),
+				tool.Dep("pysocks", "1.7.1"),
+				pytest(),
+			),
+		)},
+
+		// pyproject.toml PEP 621
+		{Path: "7", Result: tool.Dep("", "",
+			tool.Dep("pep621-demo", "1.0.0",
+				tool.Dep("Flask", ">=2.3,<3"),
+				tool.Dep("requests", "2.31.0"),
+				tool.Dep("tomli", ">=1.1"),
+				tool.Dep("redis", ">=5"),
+				tool.DevDep("pytest", ">=7"),
+				tool.DevDep("ruff", ""),
+			),
+		)},
+
+		// pyproject.toml [tool.poetry]
+		{Path: "8", Result: tool.Dep("", "",
+			tool.Dep("poetry-only", "0.3.0",
+				tool.Dep("python", "^3.10"),
+				tool.Dep("Django", "^4.2"),
+				tool.Dep("celery", "^5.3"),
+				tool.Dep("numpy", "^1.24"),
+				tool.DevDep("black", "^23.11"),
+			),
+		)},
+
+		// site-packages
+		{Path: "9", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep("requests", "2.31.0",
+					tool.Dep("certifi", "2023.11.17"),
+					tool.Dep("idna", "3.6"),
+					tool.Dep("urllib3", "2.1.0"),
+				),
+				tool.Dep("six", "1.16.0"),
+			),
+		)},
+
+		// wheel & egg
+		{Path: "10", Result: tool.Dep("", "",
+			tool.Dep("demo-pkg", "1.2.0",
+				tool.Dep("requests", ">=2.31"),
+			),
+			tool.Dep("legacy-pkg", "0.9",
+				tool.Dep("six", ">=1.10"),
+			),
+		)},
+	})
+}
+
+func Test_PythonMarker(t *testing.T) {
+
+	pypiRepo(t)
+
+	requests := func() *model.DepGraph {
+		return tool.Dep("requests", "2.31.0",
+			tool.Dep("idna", "3.6"),
+			tool.Dep("pysocks", "1.7.1"),
+		)
+	}
+
+	// 默认环境 linux python3.12
+	tool.RunTaskCase(t, python.Sca{})([]tool.TaskCase{
+
+		// requirements.txt
+		{Path: "12", Result: tool.Dep("", "", tool.Dep("", "",
+			requests(),
+		))},
+
+		// Pipfile
+		{Path: "13", Result: tool.Dep("", "", tool.Dep("", "",
+			requests(),
+		))},
+	})
+
+	python.RegisterPyEnv(python.PyEnv{PythonVersion: "3.10", SysPlatform: "win32"})
+	defer python.RegisterPyEnv(python.PyEnv{PythonVersion: "3.12", SysPlatform: "linux"})
+
+	tool.RunTaskCase(t, python.Sca{})([]tool.TaskCase{
+
+		// requirements.txt
+		{Path: "12", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("pywin32", "306"),
+			requests(),
+			tool.Dep("tomli", "2.0.1"),
+		))},
+
+		// Pipfile
+		{Path: "13", Result: tool.Dep("", "", tool.Dep("", "",
+			requests(),
+			tool.Dep("pywin32", "306"),
+			tool.Dep("tomli", "2.0.1"),
+			tool.DevDep("colorama", "0.4.6"),
+		))},
+
+		// wheel 满足环境标记的依赖
+		{Path: "10", Result: tool.Dep("", "",
+			tool.Dep("demo-pkg", "1.2.0",
+				tool.Dep("requests", ">=2.31"),
+				tool.Dep("tomli", ">=1.1"),
+			),
+			tool.Dep("legacy-pkg", "0.9",
+				tool.Dep("six", ">=1.10"),
+			),
+		)},
+	})
 }
@@ -8,19 +8,72 @@ import (
 )
 
 func Test_Ruby(t *testing.T) {
+
+	eventmachine := tool.Dep("eventmachine", "1.2.7")
+	rack := tool.Dep("rack", "3.0.8")
+
 	tool.RunTaskCase(t, ruby.Sca{})([]tool.TaskCase{
 
 		// Gemfile.lock
-		{Path: "1", Result: tool.Dep("", "",
+		{Path: "1", Result: tool.Dep("", "", tool.Dep("", "",
 			tool.Dep("em-http-request", "1.1.7",
 				tool.Dep("addressable", "2.8.5",
 					tool.Dep("public_suffix", "5.0.3"),
 				),
 				tool.Dep("cookiejar", "0.3.3"),
-				tool.Dep("em-socksify", "0.3.2"),
-				tool.Dep("eventmachine", "1.2.7"),
+				tool.Dep("em-socksify", "0.3.2", eventmachine),
+				eventmachine,
 				tool.Dep("http_parser.rb", "0.8.0"),
 			),
+		))},
+
+		// Gemfile.lock GIT/PATH段落 平台后缀 Gemfile分组
+		{Path: "2", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("blog", "0.1.0", rack),
+			tool.Dep("nokogiri", "1.15.4",
+				tool.Dep("racc", "1.7.1"),
+			),
+			tool.DevDep("pry", "0.14.2",
+				tool.DevDep("coderay", "1.1.3"),
+				tool.DevDep("method_source", "1.0.0"),
+			),
+			tool.Dep("rack-mini", "0.2.0", rack),
+			tool.DevDep("rake", "13.0.6"),
+			tool.DevDep("rspec", "3.12.0",
+				tool.DevDep("rspec-core", "3.12.2"),
+			),
+		))},
+
+		// 仅有Gemfile
+		{Path: "3", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("bootsnap", ""),
+			tool.DevDep("byebug", ""),
+			tool.DevDep("capybara", ""),
+			tool.DevDep("debug", ""),
+			tool.Dep("jdbc-postgres", ""),
+			tool.Dep("pg", "~> 1.5"),
+			tool.Dep("rails", "~> 7.1, >= 7.1.2"),
+			tool.Dep("redis", "~> 5.0"),
+			tool.DevDep("rubocop", "~> 1.50"),
+			tool.Dep("shop", "",
+				tool.Dep("money", ">= 6.0, < 7"),
+			),
+			tool.Dep("utils", ""),
+			tool.DevDep("web-console", ">= 4.1"),
+		))},
+
+		// 仅有gemspec
+		{Path: "4", Result: tool.Dep("", "",
+			tool.Dep("rack-test", "2.1.0",
+				tool.Dep("rack", ">= 1.3"),
+				tool.DevDep("rake", ">= 0"),
+			),
 		)},
 	})
+
+	// 本地组件及完整性校验值
+	tool.RunAttrCase(t, tool.Integrity, ruby.Sca{})([]tool.AttrCase{
+		{Path: "2", Want: map[string]string{"blog": "local", "rack-mini": "", "rack": "sha256=afd5ed8f1a4b6ff7e2d4d0b9a1c3ac3c3a1a1b9f5b4fd2f6d6f4bb2b4e3a6e0c"}},
+		{Path: "3", Want: map[string]string{"shop": "local", "utils": "local", "pg": ""}},
+	})
 }
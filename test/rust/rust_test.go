@@ -1,25 +1,95 @@
 package rust
 
 import (
+	"path/filepath"
 	"testing"
 
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/rust"
 	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
 )
 
 func Test_Rust(t *testing.T) {
+
+	windowsTargets := tool.Dep("windows-targets", "0.48.0",
+		tool.Dep("windows_x86_64_gnu", "0.48.0"),
+	)
+
+	libc := tool.DevDep("libc", "0.2.150")
+	core := tool.Dep("core", "0.1.0",
+		libc,
+		tool.Dep("log", "0.4.20"),
+	)
+
 	tool.RunTaskCase(t, rust.Sca{})([]tool.TaskCase{
 
 		// Cargo.lock
-		{Path: "1", Result: tool.Dep("", "",
+		{Path: "1", Result: tool.Dep("", "", tool.Dep("", "",
 			tool.Dep("foo", "0.1.0",
 				tool.Dep("tokio", "1.28.0",
-					tool.Dep("windows-sys", "0.48.0"),
+					tool.Dep("windows-sys", "0.48.0", windowsTargets),
 				),
-				tool.Dep("windows-targets", "0.48.0",
-					tool.Dep("windows_x86_64_gnu", "0.48.0"),
+				windowsTargets,
+			),
+		))},
+
+		// workspace 区分dev-dependencies及build-dependencies
+		{Path: "2", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("app", "0.1.0",
+				tool.DevDep("cc", "1.0.83", libc),
+				core,
+				tool.Dep("serde", "1.0.190"),
+				tool.DevDep("tempfile", "3.8.1",
+					tool.DevDep("fastrand", "2.0.1"),
 				),
+				tool.Dep("winapi", "0.3.9"),
+			),
+			core,
+		))},
+
+		// 仅有Cargo.toml
+		{Path: "3", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("demo", "0.2.0",
+				tool.DevDep("cc", "1"),
+				tool.DevDep("criterion", "0.5"),
+				tool.Dep("mylib", ""),
+				tool.Dep("rand", "0.8"),
+				tool.Dep("serde", "1.0"),
+				tool.Dep("serde_json", "1"),
 			),
-		)},
+		))},
+	})
+	// cargo-auditable构建的可执行文件
+	serde := tool.Dep("serde", "1.0.190")
+	tool.RunTaskCase(t, rust.Sca{})([]tool.TaskCase{
+		{Path: "4", Result: tool.Dep("", "", tool.Dep("", "",
+			tool.Dep("app", "0.1.0",
+				tool.DevDep("cc", "1.0.83"),
+				tool.Dep("mylib", "0.2.0",
+					tool.Dep("log", "0.4.20"),
+					serde,
+				),
+				serde,
+			),
+		))},
+	})
+	if rust.ParseRustBinary(model.NewFile(filepath.Join("3", "Cargo.toml"), "Cargo.toml")) != nil {
+		t.Error("Cargo.toml is not a rust binary")
+	}
+
+	// 作用域及本地/可选依赖
+	tool.RunAttrCase(t, func(n *model.DepGraph) string {
+		got := string(n.Scope)
+		if n.Local {
+			got += ":local"
+		}
+		if n.Optional {
+			got += ":optional"
+		}
+		return got
+	}, rust.Sca{})([]tool.AttrCase{
+		{Path: "2", Want: map[string]string{"cc": "build", "libc": "test", "tempfile": "test", "fastrand": "test", "winapi": "compile", "serde": "compile"}},
+		{Path: "3", Want: map[string]string{"cc": "build", "criterion": "test", "serde": "compile", "mylib": "compile:local", "rand": "compile:optional"}},
+		{Path: "4", Want: map[string]string{"cc": "build", "mylib": ":local", "app": ""}},
 	})
 }
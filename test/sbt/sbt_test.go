@@ -0,0 +1,68 @@
+package sbt
+
+import (
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/java"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/sbt"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_Sbt(t *testing.T) {
+
+	// 使用本地数据源 避免访问maven仓库
+	java.RegisterMavenOrigin(func(groupId, artifactId, version string) *java.Pom {
+		deps := map[string][]*java.PomDependency{
+			"cats-core_2.13": {{GroupId: "org.typelevel", ArtifactId: "cats-kernel_2.13", Version: "2.10.0"}},
+			"config":         {{GroupId: "org.slf4j", ArtifactId: "slf4j-api", Version: "2.0.9"}},
+		}
+		return &java.Pom{Dependencies: deps[artifactId]}
+	})
+
+	tool.RunTaskCase(t, sbt.Sca{})([]tool.TaskCase{
+
+		// build.sbt & project/plugins.sbt
+		{Path: "1", Result: tool.Dep("", "",
+			tool.Dep3("com.example", "demo", "0.1.0",
+				tool.Dep3("org.scala-lang", "scala-library", "2.13.12"),
+				tool.Dep3("org.typelevel", "cats-core_2.13", "2.10.0",
+					tool.Dep3("org.typelevel", "cats-kernel_2.13", "2.10.0"),
+				),
+				tool.Dep3("com.typesafe", "config", "1.4.3"),
+				tool.Dep3("org.postgresql", "postgresql", "42.6.0"),
+				tool.DevDep3("org.scalatest", "scalatest_2.13", "3.2.17"),
+				tool.Dep3("com.github.sbt", "sbt-native-packager_2.12_1.0", "1.9.16"),
+			),
+		)},
+
+		// build.sbt.lock
+		{Path: "2", Result: tool.Dep("", "",
+			tool.Dep("locked", "",
+				tool.Dep3("org.scala-lang", "scala3-library_3", "3.3.1"),
+				tool.Dep3("org.typelevel", "cats-core_3", "2.10.0"),
+				tool.DevDep3("org.scalatest", "scalatest_3", "3.2.17"),
+			),
+		)},
+
+		// sbt0.13 根目录的多个sbt脚本
+		{Path: "3", Result: tool.Dep("", "",
+			tool.Dep3("com.example", "legacy", "1.2.0",
+				tool.Dep3("org.scala-lang", "scala-library", "2.11.12"),
+				tool.Dep3("com.typesafe", "config", "1.4.3",
+					tool.Dep3("org.slf4j", "slf4j-api", "2.0.9"),
+				),
+				tool.Dep3("com.eed3si9n", "sbt-assembly_2.10_0.13", "0.14.10"),
+				tool.Dep3("org.scala-lang.modules", "scala-xml_2.10", "1.3.0"),
+			),
+		)},
+
+		// sbt2 没有build.sbt
+		{Path: "4", Result: tool.Dep("", "",
+			tool.Dep3("com.example", "next", "0.2.0",
+				tool.Dep3("org.scala-lang", "scala3-library_3", "3.3.1"),
+				tool.Dep3("org.typelevel", "cats-core_3", "2.10.0"),
+				tool.Dep3("com.github.sbt", "sbt-ci-release_sbt2_3", "1.9.0"),
+			),
+		)},
+	})
+}
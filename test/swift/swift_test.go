@@ -0,0 +1,67 @@
+package swift
+
+import (
+	"testing"
+
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/model"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/opensca/sca/swift"
+	"github.com/Night-Parrot/OpenSCA-cli-np/v3/test/tool"
+)
+
+func Test_Swift(t *testing.T) {
+	tool.RunTaskCase(t, swift.Sca{})([]tool.TaskCase{
+
+		// Package.resolved v1
+		{Path: "1", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep3("github.com/Alamofire", "Alamofire", "5.8.1"),
+				tool.Dep3("github.com/SnapKit", "SnapKit", "develop"),
+			),
+		)},
+
+		// Package.resolved v2 registry及本地组件
+		{Path: "2", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep3("github.com/apple", "swift-log", "1.5.3"),
+				tool.Dep("linkedlist", "1.2.0"),
+				tool.Dep3("mona", "linkedlist", "1.1.0"),
+				tool.Dep("shared", "0f3c1a5b0e6a7f8d9c2b1a0e3f4d5c6b7a8e9f01"),
+			),
+		)},
+
+		// Xcode项目中的Package.resolved v3
+		{Path: "3", Result: tool.Dep("", "",
+			tool.Dep("", "",
+				tool.Dep3("github.com/apple", "swift-collections", "1.1.0"),
+				tool.Dep3("gitlab.example.com/mobile/ios", "Kingfisher", "main"),
+			),
+		)},
+	})
+
+	// 本地组件 提交不是校验值
+	tool.RunAttrCase(t, tool.Integrity, swift.Sca{})([]tool.AttrCase{
+		{Path: "2", Want: map[string]string{
+			"swift-log": "",
+			"shared":    "local",
+		}},
+	})
+
+	// 仓库地址及锁定的提交
+	tool.RunAttrCase(t, tool.Source, swift.Sca{})([]tool.AttrCase{
+		{Path: "1", Want: map[string]string{
+			"SnapKit": "git@github.com:SnapKit/SnapKit.git#e74fe2a978d1216c3602b129447c7301573cc2d8",
+		}},
+		{Path: "2", Want: map[string]string{
+			"swift-log": "https://github.com/apple/swift-log#532d8b529501fb73a2455b179e0bbb6d49b652ed",
+			"shared":    "/Users/dev/shared#0f3c1a5b0e6a7f8d9c2b1a0e3f4d5c6b7a8e9f01",
+		}},
+	})
+
+	purl := model.Purl("github.com/apple", "swift-log", "1.5.3", model.Lan_Swift)
+	if purl != "pkg:swift/github.com/apple/swift-log@1.5.3" {
+		t.Errorf("purl:%s", purl)
+	}
+	if vendor, name, _, _ := model.ParsePurl(purl); vendor != "github.com/apple" || name != "swift-log" {
+		t.Errorf("parse purl vendor:%s name:%s", vendor, name)
+	}
+}
@@ -70,3 +70,53 @@ func RunTaskCase(t *testing.T, sca ...sca.Sca) func(cases []TaskCase) {
 		}
 	}
 }
+
+// AttrCase 组件属性用例 Want key:组件名 value:期望的属性值
+type AttrCase struct {
+	Path string
+	Want map[string]string
+}
+
+// Integrity 组件的完整性校验值 本地组件为local
+func Integrity(n *model.DepGraph) string {
+	if n.Local {
+		return "local"
+	}
+	return n.Integrity
+}
+
+// Source 组件来源及锁定的修订版本 格式为 来源#修订版本
+func Source(n *model.DepGraph) string {
+	if n.Revision == "" {
+		return n.Source
+	}
+	return n.Source + "#" + n.Revision
+}
+
+// RunAttrCase 检查检测结果中组件的属性 attr: 需要检查的属性
+// 只有一个检测器时同时检查组件语言
+func RunAttrCase(t *testing.T, attr func(n *model.DepGraph) string, sca ...sca.Sca) func(cases []AttrCase) {
+	return func(cases []AttrCase) {
+		for _, c := range cases {
+			r := opensca.RunTask(context.Background(), &opensca.TaskArg{
+				DataOrigin: c.Path,
+				Sca:        sca,
+			})
+			for _, dep := range r.Deps {
+				dep.ForEachNode(func(p, n *model.DepGraph) bool {
+					w, ok := c.Want[n.Name]
+					if !ok {
+						return true
+					}
+					if got := attr(n); got != w {
+						t.Errorf("%s %s got:%s want:%s", c.Path, n.Index(), got, w)
+					}
+					if len(sca) == 1 && n.Language != sca[0].Language() {
+						t.Errorf("%s %s language:%s", c.Path, n.Index(), n.Language)
+					}
+					return true
+				})
+			}
+		}
+	}
+}